@@ -49,9 +49,10 @@ func (p *Program) String() string {
 	return out.String()
 }
 
-// LetStatement 节点 解析 let 语句
+// LetStatement 节点 解析 let 语句及带类型标注的声明（如 int x = 1;）
 type LetStatement struct {
 	Token token.Token
+	Type  *Identifier // 声明的类型，let 声明时为 nil 表示由初始值推断
 	Name  *Identifier
 	Value Expression
 }
@@ -246,6 +247,36 @@ func (fl *ForExpression) String() string {
 	return out.String()
 }
 
+// ForInExpression 节点 解析 for (x in arr) 与 for (i, x in arr) 循环
+type ForInExpression struct {
+	Token    token.Token
+	Key      *Identifier // 下标或键，只有一个循环变量时为 nil
+	Value    *Identifier // 元素、值或字符
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fi *ForInExpression) expressionNode() {}
+func (fi *ForInExpression) TokenLiteral() string {
+	return fi.Token.Literal
+}
+func (fi *ForInExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fi.Key != nil {
+		out.WriteString(fi.Key.String() + ", ")
+	}
+	out.WriteString(fi.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fi.Iterable.String())
+	out.WriteString(") ")
+	if fi.Body != nil {
+		out.WriteString(fi.Body.String())
+	}
+	return out.String()
+}
+
 // BlockStatement 节点 解析块语句
 type BlockStatement struct {
 	Token      token.Token // {
@@ -266,9 +297,11 @@ func (bs *BlockStatement) String() string {
 
 // FunctionLiteral 节点 解析函数字面量
 type FunctionLiteral struct {
-	Token      token.Token // fn
-	Parameters []*Identifier
-	Body       *BlockStatement
+	Token          token.Token // fn
+	Parameters     []*Identifier
+	ParameterTypes []*Identifier // 与 Parameters 一一对应，未标注类型的参数为 nil
+	ReturnType     *Identifier   // 返回值类型，未标注时为 nil
+	Body           *BlockStatement
 }
 
 func (fl *FunctionLiteral) expressionNode() {}
@@ -278,14 +311,21 @@ func (fl *FunctionLiteral) TokenLiteral() string {
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 	params := []string{}
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
+	for i, p := range fl.Parameters {
+		if i < len(fl.ParameterTypes) && fl.ParameterTypes[i] != nil {
+			params = append(params, fl.ParameterTypes[i].String()+" "+p.String())
+		} else {
+			params = append(params, p.String())
+		}
 	}
 
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") ")
+	if fl.ReturnType != nil {
+		out.WriteString(fl.ReturnType.String() + " ")
+	}
 	out.WriteString(fl.Body.String())
 
 	return out.String()
@@ -349,6 +389,145 @@ func (as *AssignStatement) String() string {
 	return out.String()
 }
 
+// MemberAssignStatement 节点 解析成员赋值语句 obj.name = value
+type MemberAssignStatement struct {
+	Token  token.Token // =
+	Target *MemberExpression
+	Value  Expression
+}
+
+func (ma *MemberAssignStatement) statementNode() {}
+func (ma *MemberAssignStatement) TokenLiteral() string {
+	return ma.Token.Literal
+}
+func (ma *MemberAssignStatement) String() string {
+	return ma.Target.String() + " = " + ma.Value.String()
+}
+
+// StructStatement 节点 解析结构体声明 struct Point { int x, y }
+type StructStatement struct {
+	Token      token.Token // struct
+	Name       *Identifier
+	Fields     []*Identifier
+	FieldTypes []*Identifier // 字段的类型标注，未标注的为 nil
+}
+
+func (ss *StructStatement) statementNode() {}
+func (ss *StructStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+func (ss *StructStatement) String() string {
+	fields := []string{}
+	for i, field := range ss.Fields {
+		if ss.FieldTypes[i] != nil {
+			fields = append(fields, ss.FieldTypes[i].String()+" "+field.String())
+		} else {
+			fields = append(fields, field.String())
+		}
+	}
+	return "struct " + ss.Name.String() + " { " + strings.Join(fields, ", ") + " }"
+}
+
+// ImportStatement 节点 解析 import 语句
+type ImportStatement struct {
+	Token token.Token // import
+	Path  string
+	Alias *Identifier // as 之后的名称，未指定时为 nil
+}
+
+// Name 返回导入后绑定的名称：指定了别名时为别名，否则为路径的文件名（去掉扩展名）
+func (is *ImportStatement) Name() string {
+	if is.Alias != nil {
+		return is.Alias.Value
+	}
+	name := is.Path
+	if i := strings.LastIndexAny(name, "/\\"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func (is *ImportStatement) statementNode() {}
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+func (is *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(is.TokenLiteral() + " ")
+	out.WriteString("\"" + is.Path + "\"")
+	if is.Alias != nil {
+		out.WriteString(" as " + is.Alias.String())
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
+// ExportStatement 节点 解析 export 声明，导出模块顶层变量
+type ExportStatement struct {
+	Token       token.Token // export
+	Declaration *LetStatement
+}
+
+func (es *ExportStatement) statementNode() {}
+func (es *ExportStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Declaration.String()
+}
+
+// ThrowStatement 节点 解析 throw 语句
+type ThrowStatement struct {
+	Token token.Token // throw
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode() {}
+func (ts *ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+func (ts *ThrowStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString(ts.TokenLiteral() + " ")
+	if ts.Value != nil {
+		out.WriteString(ts.Value.String())
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
+// TryStatement 节点 解析 try/catch/finally 语句
+type TryStatement struct {
+	Token      token.Token // try
+	Block      *BlockStatement
+	CatchParam *Identifier     // catch 绑定错误的变量名
+	Catch      *BlockStatement // 未写 catch 时为 nil
+	Finally    *BlockStatement // 未写 finally 时为 nil
+}
+
+func (ts *TryStatement) statementNode() {}
+func (ts *TryStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("try ")
+	out.WriteString(ts.Block.String())
+	if ts.Catch != nil {
+		out.WriteString(" catch(" + ts.CatchParam.String() + ") ")
+		out.WriteString(ts.Catch.String())
+	}
+	if ts.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(ts.Finally.String())
+	}
+	return out.String()
+}
+
 // ArrayLiteral 节点 解析数组字面量
 type ArrayLiteral struct {
 	Token    token.Token // [
@@ -396,10 +575,56 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+// SliceExpression 节点 解析切片表达式 a[start:end]，省略的边界为 nil
+type SliceExpression struct {
+	Token token.Token // [
+	Left  Expression
+	Start Expression
+	End   Expression
+}
+
+func (se *SliceExpression) expressionNode() {}
+func (se *SliceExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	out.WriteString("]")
+	out.WriteString(")")
+	return out.String()
+}
+
+// MemberExpression 节点 解析成员访问 obj.name
+type MemberExpression struct {
+	Token    token.Token // .
+	Object   Expression
+	Property *Identifier
+}
+
+func (me *MemberExpression) expressionNode() {}
+func (me *MemberExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+func (me *MemberExpression) String() string {
+	return "(" + me.Object.String() + "." + me.Property.String() + ")"
+}
+
 // HashLiteral 节点 解析哈希字面量
 type HashLiteral struct {
 	Token token.Token
 	Pairs map[Expression]Expression
+	Keys  []Expression // 键的书写顺序
 }
 
 func (hl *HashLiteral) expressionNode() {}
@@ -410,8 +635,8 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, key := range hl.Keys {
+		pairs = append(pairs, key.String()+":"+hl.Pairs[key].String())
 	}
 
 	out.WriteString("{")
@@ -420,3 +645,23 @@ func (hl *HashLiteral) String() string {
 
 	return out.String()
 }
+
+// StructLiteral 节点 解析结构体字面量 Point{x: 1, y: 2}
+type StructLiteral struct {
+	Token  token.Token // 结构体名
+	Name   *Identifier
+	Fields []*Identifier
+	Values []Expression
+}
+
+func (sl *StructLiteral) expressionNode() {}
+func (sl *StructLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+func (sl *StructLiteral) String() string {
+	fields := []string{}
+	for i, field := range sl.Fields {
+		fields = append(fields, field.String()+": "+sl.Values[i].String())
+	}
+	return sl.Name.String() + "{" + strings.Join(fields, ", ") + "}"
+}
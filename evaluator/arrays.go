@@ -0,0 +1,291 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"sort"
+)
+
+/**
+ * @Description: 数组内置函数，其中的回调函数通过 applyFunction 调用脚本函数或内置函数
+ */
+
+var arrayBuiltins = map[string]*object.Builtin{
+	"map": { //对每个元素调用 fn，返回结果组成的新数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkCallbackArgs("map", args); err != nil {
+				return err
+			}
+			elements := args[0].(*object.Array).Elements
+			if err := checkAlloc(rt, len(elements)); err != nil {
+				return err
+			}
+			result := make([]object.Object, len(elements))
+			for i, el := range elements {
+				value := applyFunction(args[1], []object.Object{el}, rt)
+				if isError(value) {
+					return value
+				}
+				result[i] = value
+			}
+			return &object.Array{Elements: result}
+		},
+	},
+	"filter": { //保留 fn 返回真值的元素
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkCallbackArgs("filter", args); err != nil {
+				return err
+			}
+			result := []object.Object{}
+			for _, el := range args[0].(*object.Array).Elements {
+				keep := applyFunction(args[1], []object.Object{el}, rt)
+				if isError(keep) {
+					return keep
+				}
+				if isTruthy(keep) {
+					result = append(result, el)
+				}
+			}
+			if err := checkAlloc(rt, len(result)); err != nil {
+				return err
+			}
+			return &object.Array{Elements: result}
+		},
+	},
+	"reduce": { //以 fn(acc, x) 依次累积元素，省略初始值时以第一个元素作为初始值
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newError("wrong number of arguments. got=%d, want=2 or 3", len(args))
+			}
+			if err := checkCallbackArgs("reduce", args[:2]); err != nil {
+				return err
+			}
+
+			elements := args[0].(*object.Array).Elements
+			var acc object.Object
+			if len(args) == 3 {
+				acc = args[2]
+			} else if len(elements) > 0 {
+				acc, elements = elements[0], elements[1:]
+			} else {
+				return newError("`reduce` of empty array with no initial value")
+			}
+
+			for _, el := range elements {
+				acc = applyFunction(args[1], []object.Object{acc, el}, rt)
+				if isError(acc) {
+					return acc
+				}
+			}
+			return acc
+		},
+	},
+	"sort": { //返回排序后的新数组，可选的比较函数 cmp(a, b) 返回负数、零、正数或 a 是否排在 b 之前
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) == 2 {
+				if err := checkCallbackArgs("sort", args); err != nil {
+					return err
+				}
+				return sortWithComparator(args[0].(*object.Array), args[1], rt)
+			}
+			if err := checkArgs("sort", args, object.ARRAY_OBJ); err != nil {
+				return err
+			}
+			return sortNatural(args[0].(*object.Array), rt)
+		},
+	},
+	"reverse": { //返回元素顺序相反的新数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("reverse", args, object.ARRAY_OBJ); err != nil {
+				return err
+			}
+			elements := args[0].(*object.Array).Elements
+			if err := checkAlloc(rt, len(elements)); err != nil {
+				return err
+			}
+			result := make([]object.Object, len(elements))
+			for i, el := range elements {
+				result[len(elements)-1-i] = el
+			}
+			return &object.Array{Elements: result}
+		},
+	},
+	"range": { //range(end)、range(start, end)、range(start, end, step) 生成整数数组，不包含 end
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 3 {
+				return newError("wrong number of arguments. got=%d, want=1 to 3", len(args))
+			}
+			bounds := []int64{0, 0, 1}
+			for i, arg := range args {
+				integer, ok := arg.(*object.Integer)
+				if !ok {
+					return newError("argument %d to `range` must be INTEGER, got %s", i+1, arg.Type())
+				}
+				bounds[i] = integer.Value
+			}
+			if len(args) == 1 {
+				bounds[0], bounds[1] = 0, bounds[0]
+			}
+			return integerRange(bounds[0], bounds[1], bounds[2], rt)
+		},
+	},
+	"zip": { //将两个数组对应位置的元素组成 [a, b]，长度取较短的数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("zip", args, object.ARRAY_OBJ, object.ARRAY_OBJ); err != nil {
+				return err
+			}
+			left := args[0].(*object.Array).Elements
+			right := args[1].(*object.Array).Elements
+			n := len(left)
+			if len(right) < n {
+				n = len(right)
+			}
+			if err := checkAlloc(rt, n); err != nil {
+				return err
+			}
+			result := make([]object.Object, n)
+			for i := 0; i < n; i++ {
+				result[i] = &object.Array{Elements: []object.Object{left[i], right[i]}}
+			}
+			return &object.Array{Elements: result}
+		},
+	},
+	"any": { //是否存在满足 fn 的元素，省略 fn 时判断元素本身
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return evalQuantifier("any", true, args, rt)
+		},
+	},
+	"all": { //是否所有元素都满足 fn，省略 fn 时判断元素本身
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return evalQuantifier("all", false, args, rt)
+		},
+	},
+}
+
+// checkCallbackArgs 校验 (数组, 函数) 形式的参数
+func checkCallbackArgs(name string, args []object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.ARRAY_OBJ {
+		return newError("argument 1 to `%s` must be ARRAY, got %s", name, args[0].Type())
+	}
+	if args[1].Type() != object.FUNCTION_OBJ && args[1].Type() != object.BUILTIN_OBJ {
+		return newError("argument 2 to `%s` must be FUNCTION, got %s", name, args[1].Type())
+	}
+	return nil
+}
+
+// evalQuantifier 实现 any 与 all：遇到结果等于 stopOn 的元素时立即返回 stopOn
+func evalQuantifier(name string, stopOn bool, args []object.Object, rt *object.Runtime) object.Object {
+	if len(args) == 2 {
+		if err := checkCallbackArgs(name, args); err != nil {
+			return err
+		}
+	} else if err := checkArgs(name, args, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+
+	for _, el := range args[0].(*object.Array).Elements {
+		result := el
+		if len(args) == 2 {
+			result = applyFunction(args[1], []object.Object{el}, rt)
+			if isError(result) {
+				return result
+			}
+		}
+		if isTruthy(result) == stopOn {
+			return nativeBoolToBooleanObject(stopOn)
+		}
+	}
+	return nativeBoolToBooleanObject(!stopOn)
+}
+
+// sortNatural 按自然顺序排序，元素必须全部为整数或全部为字符串
+func sortNatural(array *object.Array, rt *object.Runtime) object.Object {
+	if err := checkAlloc(rt, len(array.Elements)); err != nil {
+		return err
+	}
+	result := make([]object.Object, len(array.Elements))
+	copy(result, array.Elements)
+	if len(result) == 0 {
+		return &object.Array{Elements: result}
+	}
+
+	elemType := result[0].Type()
+	for _, el := range result {
+		if el.Type() != elemType || (elemType != object.INTEGER_OBJ && elemType != object.STRING_OBJ) {
+			return newError("`sort` without comparator needs all INTEGER or all STRING elements, got %s", el.Type())
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if elemType == object.INTEGER_OBJ {
+			return result[i].(*object.Integer).Value < result[j].(*object.Integer).Value
+		}
+		return result[i].(*object.String).Value < result[j].(*object.String).Value
+	})
+	return &object.Array{Elements: result}
+}
+
+// sortWithComparator 使用比较函数稳定排序，比较函数出错时中止排序并返回该错误
+func sortWithComparator(array *object.Array, cmp object.Object, rt *object.Runtime) object.Object {
+	if err := checkAlloc(rt, len(array.Elements)); err != nil {
+		return err
+	}
+	result := make([]object.Object, len(array.Elements))
+	copy(result, array.Elements)
+
+	var failure object.Object
+	sort.SliceStable(result, func(i, j int) bool {
+		if failure != nil {
+			return false
+		}
+		order := applyFunction(cmp, []object.Object{result[i], result[j]}, rt)
+		switch order := order.(type) {
+		case *object.Integer:
+			return order.Value < 0
+		case *object.Boolean:
+			return order.Value
+		case *object.Error:
+			failure = order
+		default:
+			failure = newError("`sort` comparator must return INTEGER or BOOLEAN, got %s", order.Type())
+		}
+		return false
+	})
+
+	if failure != nil {
+		return failure
+	}
+	return &object.Array{Elements: result}
+}
+
+// integerRange 生成 [start, end) 内步长为 step 的整数数组
+func integerRange(start, end, step int64, rt *object.Runtime) object.Object {
+	if step == 0 {
+		return newError("`range` step must not be zero")
+	}
+
+	// 使用无符号数计算元素个数，避免 end - start 溢出
+	var n uint64
+	if step > 0 && end > start {
+		n = (uint64(end)-uint64(start)-1)/uint64(step) + 1
+	} else if step < 0 && start > end {
+		n = (uint64(start)-uint64(end)-1)/(-uint64(step)) + 1
+	}
+	if n > maxRangeLength {
+		return newKindError(object.OVERFLOW_ERROR, "`range` too large: %d elements", n)
+	}
+	if err := checkAlloc(rt, int(n)); err != nil {
+		return err
+	}
+
+	result := make([]object.Object, int(n))
+	for i := range result {
+		result[i] = &object.Integer{Value: start + int64(i)*step}
+	}
+	return &object.Array{Elements: result}
+}
+
+// range 生成的数组长度上限，防止未设置 MaxAlloc 时耗尽内存
+const maxRangeLength = 1 << 26
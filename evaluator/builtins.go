@@ -3,6 +3,9 @@ package evaluator
 import (
 	"Cmicro-Compiler/object"
 	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
 )
 
 /**
@@ -11,49 +14,70 @@ import (
 
 var builtins = map[string]*object.Builtin{
 	"len": { //长度函数
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1", len(args))
 			}
 
 			switch arg := args[0].(type) {
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.Hash:
+				return &object.Integer{Value: int64(len(arg.Pairs))}
 			default:
 				return newError("argument to `len` not supported, got %s", args[0].Type())
 			}
 		},
 	},
 	"println": { //打印函数 换行
-		Fn: func(args ...object.Object) object.Object {
-			for _, arg := range args {
-				fmt.Printf("%v\n", arg.Inspect())
-			}
-			return nil
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return writeArgs(rt.Stdout, "println", "\n", args)
 		},
 	},
 	"print": { //打印函数
-		Fn: func(args ...object.Object) object.Object {
-			for _, arg := range args {
-				fmt.Printf("%v", arg.Inspect())
-			}
-			return nil
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return writeArgs(rt.Stdout, "print", "", args)
+		},
+	},
+	"eprintln": { //打印到标准错误 换行
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return writeArgs(rt.Stderr, "eprintln", "\n", args)
 		},
 	},
-	"input": { //输入函数
-		Fn: func(args ...object.Object) object.Object {
-			var input string
-			_, err := fmt.Scanln(&input)
-			if err != nil {
-				return newError("argument to `input` not supported, got %s", args[0].Type())
-			}
-			return &object.String{Value: input}
+	"eprint": { //打印到标准错误
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return writeArgs(rt.Stderr, "eprint", "", args)
+		},
+	},
+	"input": { //输入函数 读取一整行，可选参数为提示语
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newError("wrong number of arguments. got=%d, want=0 or 1", len(args))
+			}
+			if len(args) == 1 {
+				prompt, ok := args[0].(*object.String)
+				if !ok {
+					return newError("argument to `input` must be STRING, got %s", args[0].Type())
+				}
+				if _, err := io.WriteString(rt.Stdout, prompt.Value); err != nil {
+					return newError("input: %s", err)
+				}
+			}
+
+			line, err := rt.Stdin.ReadString('\n')
+			if err == io.EOF && line == "" { //输入结束
+				return NULL
+			}
+			if err != nil && err != io.EOF {
+				return newError("input: %s", err)
+			}
+			return &object.String{Value: strings.TrimRight(line, "\r\n")}
 		},
 	},
 	"first": { //取数组第一个元素
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1", len(args))
 			}
@@ -70,7 +94,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"last": { //取数组最后一个元素
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1", len(args))
 			}
@@ -89,7 +113,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"rest": { //取数组除最后一个元素
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1", len(args))
 			}
@@ -108,7 +132,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"push": { //向数组中添加元素
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return newError("wrong number of arguments. got=%d, want=2", len(args))
 			}
@@ -119,6 +143,9 @@ var builtins = map[string]*object.Builtin{
 
 			arr := args[0].(*object.Array)
 			length := len(arr.Elements)
+			if err := checkAlloc(rt, length+1); err != nil {
+				return err
+			}
 
 			newElements := make([]object.Object, length+1)
 			copy(newElements, arr.Elements)
@@ -128,3 +155,23 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 }
+
+// 按类别定义在其他文件中的内置函数，在 init 中合并到 builtins，
+// 以便其中调用 applyFunction 的函数不会与 builtins 形成初始化循环
+func init() {
+	for _, group := range []map[string]*object.Builtin{stringBuiltins, arrayBuiltins, hashBuiltins, mathBuiltins, typeBuiltins, fileBuiltins, jsonBuiltins, systemBuiltins, timeBuiltins, regexpBuiltins} {
+		for name, builtin := range group {
+			builtins[name] = builtin
+		}
+	}
+}
+
+// writeArgs 将参数依次写入输出流，每个参数之后追加 sep
+func writeArgs(w io.Writer, name, sep string, args []object.Object) object.Object {
+	for _, arg := range args {
+		if _, err := fmt.Fprintf(w, "%v%s", arg.Inspect(), sep); err != nil {
+			return newError("%s: %s", name, err)
+		}
+	}
+	return nil
+}
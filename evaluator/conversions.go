@@ -0,0 +1,98 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"errors"
+	"strconv"
+)
+
+/**
+ * @Description: 类型判断与类型转换内置函数
+ */
+
+var typeBuiltins = map[string]*object.Builtin{
+	"type": { //返回值的类型名，如 INTEGER、STRING
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			return &object.String{Value: string(args[0].Type())}
+		},
+	},
+	"str": { //转换为字符串，与打印的结果相同
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			if str, ok := args[0].(*object.String); ok {
+				return str
+			}
+			return &object.String{Value: args[0].Inspect()}
+		},
+	},
+	"int": { //转换为整数，字符串按十进制解析，true 为 1，false 为 0
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.Integer:
+				return arg
+			case *object.Boolean:
+				if arg.Value {
+					return &object.Integer{Value: 1}
+				}
+				return &object.Integer{Value: 0}
+			case *object.String:
+				return parseInteger(arg.Value)
+			default:
+				return newKindError(object.TYPE_ERROR, "cannot convert %s to INTEGER", arg.Type())
+			}
+		},
+	},
+	"bool": { //转换为布尔值，与条件判断的规则相同
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			return nativeBoolToBooleanObject(isTruthy(args[0]))
+		},
+	},
+	"is_int":      typePredicate(object.INTEGER_OBJ),
+	"is_string":   typePredicate(object.STRING_OBJ),
+	"is_bool":     typePredicate(object.BOOLEAN_OBJ),
+	"is_null":     typePredicate(object.NULL_OBJ),
+	"is_array":    typePredicate(object.ARRAY_OBJ),
+	"is_hash":     typePredicate(object.HASH_OBJ),
+	"is_struct":   typePredicate(object.STRUCT_OBJ),
+	"is_function": typePredicate(object.FUNCTION_OBJ, object.BUILTIN_OBJ),
+}
+
+// typePredicate 创建判断参数是否为给定类型之一的内置函数
+func typePredicate(types ...object.ObjectType) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			for _, t := range types {
+				if args[0].Type() == t {
+					return TRUE
+				}
+			}
+			return FALSE
+		},
+	}
+}
+
+// parseInteger 将十进制字符串解析为整数
+func parseInteger(s string) object.Object {
+	value, err := strconv.ParseInt(s, 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return newKindError(object.OVERFLOW_ERROR, "integer out of range: %q", s)
+	}
+	if err != nil {
+		return newError("invalid integer: %q", s)
+	}
+	return &object.Integer{Value: value}
+}
@@ -0,0 +1,88 @@
+package evaluator
+
+import "Cmicro-Compiler/object"
+
+/**
+ * @Description: 值的结构相等比较
+ */
+
+// objectsEqual 判断两个值是否相等：整数、字符串按值比较，数组逐个比较元素，
+// 哈希表比较键集合与对应的值，结构体比较类型与各字段，其余按引用比较
+func objectsEqual(a, b object.Object) bool {
+	return deepEqual(a, b, make(map[[2]object.Object]bool))
+}
+
+// deepEqual 递归比较，visited 记录已经开始比较的容器对，再次遇到时视为相等，避免循环引用导致无限递归；
+// 任意一处不相等都会使整个比较立即返回 false，因此视为相等不会掩盖差异
+func deepEqual(a, b object.Object, visited map[[2]object.Object]bool) bool {
+	if a == b {
+		return true
+	}
+
+	switch a := a.(type) {
+	case *object.Integer:
+		b, ok := b.(*object.Integer)
+		return ok && a.Value == b.Value
+	case *object.String:
+		b, ok := b.(*object.String)
+		return ok && a.Value == b.Value
+	case *object.Boolean:
+		b, ok := b.(*object.Boolean)
+		return ok && a.Value == b.Value
+	case *object.Null:
+		_, ok := b.(*object.Null)
+		return ok
+	case *object.Array:
+		b, ok := b.(*object.Array)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		return visit(a, b, visited, func() bool {
+			for i := range a.Elements {
+				if !deepEqual(a.Elements[i], b.Elements[i], visited) {
+					return false
+				}
+			}
+			return true
+		})
+	case *object.Hash:
+		b, ok := b.(*object.Hash)
+		if !ok || len(a.Pairs) != len(b.Pairs) {
+			return false
+		}
+		return visit(a, b, visited, func() bool {
+			for key, pair := range a.Pairs {
+				other, ok := b.Pairs[key]
+				if !ok || !deepEqual(pair.Value, other.Value, visited) {
+					return false
+				}
+			}
+			return true
+		})
+	case *object.Struct:
+		b, ok := b.(*object.Struct)
+		if !ok || a.StructType != b.StructType {
+			return false
+		}
+		return visit(a, b, visited, func() bool {
+			for i := range a.Fields {
+				if !deepEqual(a.Fields[i], b.Fields[i], visited) {
+					return false
+				}
+			}
+			return true
+		})
+	default:
+		return false
+	}
+}
+
+// visit 标记容器对并比较，已经比较过的容器对直接视为相等
+func visit(a, b object.Object, visited map[[2]object.Object]bool, compare func() bool) bool {
+	pair := [2]object.Object{a, b}
+	if visited[pair] {
+		return true
+	}
+	visited[pair] = true
+	return compare()
+}
@@ -4,6 +4,8 @@ import (
 	"Cmicro-Compiler/ast"
 	"Cmicro-Compiler/object"
 	"fmt"
+	"math"
+	"strings"
 )
 
 /**
@@ -16,8 +18,23 @@ var (
 	FALSE = &object.Boolean{Value: false}
 )
 
-// Eval 节点求值
+// Eval 节点求值，产生的错误会记录最先出错的节点位置
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	if err := step(env.Runtime()); err != nil {
+		return err
+	}
+
+	result := evalNode(node, env)
+	if errObj, ok := result.(*object.Error); ok && errObj.Line == 0 {
+		if tok, ok := nodeToken(node); ok {
+			errObj.Line, errObj.Column = tok.Line, tok.Column
+		}
+	}
+	return result
+}
+
+// evalNode 根据节点类型求值
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program: // 程序嵌套
 		return evalProgram(node, env)
@@ -32,8 +49,11 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(right) {
 			return right
 		}
-		result := evalPrefixExpression(node.Operator, right)
-		env.Set(node.Right.String(), result)
+		result := evalPrefixExpression(node.Operator, right, env.Runtime())
+		// 自增自减运算写回变量
+		if _, ok := node.Right.(*ast.Identifier); ok && (node.Operator == "++" || node.Operator == "--") && !isError(result) {
+			env.Assign(node.Right.String(), result)
+		}
 		return result
 	case *ast.InfixExpression: // 中缀运算符
 		left := Eval(node.Left, env)
@@ -44,7 +64,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(right) {
 			return right
 		}
-		return evalInfixExpression(node.Operator, left, right)
+		return evalInfixExpression(node.Operator, left, right, env.Runtime())
 	case *ast.BlockStatement: // 语句块
 		return evalBlockStatement(node, env)
 	case *ast.IfExpression: // if条件
@@ -63,8 +83,24 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		env.Set(node.Name.Value, val)
 	case *ast.AssignStatement: //变量赋值
 		return evalAssignStatement(node, env)
+	case *ast.MemberAssignStatement: // 成员赋值
+		return evalMemberAssignStatement(node, env)
+	case *ast.StructStatement: // 结构体声明
+		return evalStructStatement(node, env)
+	case *ast.StructLiteral: // 结构体字面量
+		return evalStructLiteral(node, env)
+	case *ast.ImportStatement: // 导入模块
+		return evalImportStatement(node, env)
+	case *ast.ExportStatement: // 导出声明
+		return Eval(node.Declaration, env)
+	case *ast.ThrowStatement: // 抛出错误
+		return evalThrowStatement(node, env)
+	case *ast.TryStatement: // 捕获错误
+		return evalTryStatement(node, env)
 	case *ast.ForExpression: // for循环
 		return evalForExpression(node, env)
+	case *ast.ForInExpression: // for-in循环
+		return evalForInExpression(node, env)
 	case *ast.Identifier: // 变量
 		return evalIdentifier(node, env)
 	case *ast.StringLiteral:
@@ -84,13 +120,22 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return args[0]
 		}
 
-		return applyFunction(function, args)
+		return applyFunction(function, args, env.Runtime())
 	case *ast.ArrayLiteral: // 数组
 		elements := evalExpressions(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
 			return elements[0]
 		}
+		if err := checkAlloc(env.Runtime(), len(elements)); err != nil {
+			return err
+		}
 		return &object.Array{Elements: elements}
+	case *ast.MemberExpression: // 成员访问
+		obj := Eval(node.Object, env)
+		if isError(obj) {
+			return obj
+		}
+		return evalMemberExpression(obj, node.Property.Value)
 	case *ast.IndexExpression: // 数组索引
 		left := Eval(node.Left, env)
 		if isError(left) {
@@ -100,7 +145,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(index) {
 			return index
 		}
-		return evalIndexExpression(left, index)
+		return evalIndexExpression(left, index, env.Runtime())
+	case *ast.SliceExpression: // 切片
+		return evalSliceExpression(node, env)
 	case *ast.HashLiteral: // 哈希表
 		return evalHashLiteral(node, env)
 	}
@@ -146,11 +193,15 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 		return val
 	}
 
+	if builtin, ok := env.Runtime().Builtins[node.Value]; ok {
+		return builtin
+	}
+
 	if builtin, ok := builtins[node.Value]; ok {
 		return builtin
 	}
 
-	return newError("identifier not found: " + node.Value)
+	return newKindError(object.NAME_ERROR, "identifier not found: "+node.Value)
 }
 func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
 	// 遍历表达式列表，在当前环境的上下文中求值，如果遇到错误，就停止求值并返回错误
@@ -165,16 +216,24 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 
 	return result
 }
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+func applyFunction(fn object.Object, args []object.Object, rt *object.Runtime) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
+		if len(args) < len(fn.Parameters) {
+			return newKindError(object.TYPE_ERROR, "wrong number of arguments. got=%d, want=%d", len(args), len(fn.Parameters))
+		}
+		if err := enterCall(rt); err != nil {
+			return err
+		}
+		defer exitCall(rt)
+
 		extendedEnv := extendFunctionEnv(fn, args)
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 	case *object.Builtin:
-		return fn.Fn(args...)
+		return fn.Fn(rt, args...)
 	default:
-		return newError("not a function: %s", fn.Type())
+		return newKindError(object.TYPE_ERROR, "not a function: %s", fn.Type())
 	}
 
 }
@@ -196,50 +255,66 @@ func unwrapReturnValue(obj object.Object) object.Object {
 }
 
 // evalIndexExpression 索引表达式匹配求值方法
-func evalIndexExpression(left, index object.Object) object.Object {
+func evalIndexExpression(left, index object.Object, rt *object.Runtime) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
-		return evalArrayIndexExpression(left, index)
+		return evalArrayIndexExpression(left, index, rt)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index, rt)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
+	case left.Type() == object.MODULE_OBJ && index.Type() == object.STRING_OBJ:
+		return evalModuleMember(left.(*object.Module), index.(*object.String).Value)
+	case left.Type() == object.EXCEPTION_OBJ && index.Type() == object.STRING_OBJ:
+		return evalExceptionField(left.(*object.Exception), index.(*object.String).Value)
 	default:
-		return newError("index operator not supported: %s", left.Type())
+		return newKindError(object.TYPE_ERROR, "index operator not supported: %s", left.Type())
 	}
 }
-func evalArrayIndexExpression(array, index object.Object) object.Object {
+func evalArrayIndexExpression(array, index object.Object, rt *object.Runtime) object.Object {
 	// 数组索引
 	arrayObject := array.(*object.Array)
-	idx := index.(*object.Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
-
-	if idx < 0 || idx > max {
-		return NULL
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(arrayObject.Elements), rt)
+	if !ok {
+		return indexOutOfRange(index.(*object.Integer).Value, len(arrayObject.Elements), rt)
 	}
 	return arrayObject.Elements[idx]
 }
 
+// evalStringIndexExpression 字符串索引，下标按 UTF-8 字符计算，返回对应位置的单个字符
+func evalStringIndexExpression(str, index object.Object, rt *object.Runtime) object.Object {
+	runes := []rune(str.(*object.String).Value)
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(runes), rt)
+	if !ok {
+		return indexOutOfRange(index.(*object.Integer).Value, len(runes), rt)
+	}
+	return &object.String{Value: string(runes[idx])}
+}
+
 // evalHashLiteral 哈希表匹配求值方法
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
-	for keyNode, valueNode := range node.Pairs {
+	for _, keyNode := range node.Keys {
 		key := Eval(keyNode, env)
 		if isError(key) {
 			return key
 		}
 		hashKey, ok := key.(object.Hashable)
 		if !ok {
-			return newError("unusable as hash key: %s", key.Type())
+			return newKindError(object.KEY_ERROR, "unusable as hash key: %s", key.Type())
 		}
-		value := Eval(valueNode, env)
+		value := Eval(node.Pairs[keyNode], env)
 		if isError(value) {
 			return value
 		}
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
 	}
 
-	return &object.Hash{Pairs: pairs}
+	if err := checkAlloc(env.Runtime(), len(hash.Pairs)); err != nil {
+		return err
+	}
+	return hash
 }
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	// 哈希表索引
@@ -247,7 +322,7 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 
 	key, ok := index.(object.Hashable)
 	if !ok {
-		return newError("unusable as hash key: %s", index.Type())
+		return newKindError(object.KEY_ERROR, "unusable as hash key: %s", index.Type())
 	}
 
 	pair, ok := hashObject.Pairs[key.HashKey()]
@@ -267,18 +342,18 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 }
 
 // evalPrefixExpression 前缀表达式匹配求值方法
-func evalPrefixExpression(operator string, right object.Object) object.Object {
+func evalPrefixExpression(operator string, right object.Object, rt *object.Runtime) object.Object {
 	switch operator {
 	case "!":
 		return evalBangOperatorExpression(right)
 	case "-":
-		return evalMinusPrefixOperatorExpression(right)
+		return evalMinusPrefixOperatorExpression(right, rt)
 	case "++":
-		return evalIncrementPrefixOperatorExpression(right)
+		return evalIncrementPrefixOperatorExpression(right, rt)
 	case "--":
-		return evalDecrementPrefixOperatorExpression(right)
+		return evalDecrementPrefixOperatorExpression(right, rt)
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s%s", operator, right.Type())
 	}
 }
 func evalBangOperatorExpression(right object.Object) object.Object {
@@ -296,63 +371,89 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 }
 
 // evalMinusPrefixOperatorExpression 前缀表达式求值
-func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+func evalMinusPrefixOperatorExpression(right object.Object, rt *object.Runtime) object.Object {
 	if right.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: -%s", right.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: -%s", right.Type())
 	}
 
 	value := right.(*object.Integer).Value
+	if rt.CheckedArithmetic && value == math.MinInt64 {
+		return newKindError(object.OVERFLOW_ERROR, "integer overflow: -%d", value)
+	}
 	return &object.Integer{Value: -value}
 }
-func evalIncrementPrefixOperatorExpression(right object.Object) object.Object {
+func evalIncrementPrefixOperatorExpression(right object.Object, rt *object.Runtime) object.Object {
 	if right.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: ++%s", right.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: ++%s", right.Type())
 	}
 
 	value := right.(*object.Integer).Value
+	if rt.CheckedArithmetic && value == math.MaxInt64 {
+		return newKindError(object.OVERFLOW_ERROR, "integer overflow: ++%d", value)
+	}
 	return &object.Integer{Value: value + 1}
 }
-func evalDecrementPrefixOperatorExpression(right object.Object) object.Object {
+func evalDecrementPrefixOperatorExpression(right object.Object, rt *object.Runtime) object.Object {
 	if right.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: --%s", right.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: --%s", right.Type())
 	}
 
 	value := right.(*object.Integer).Value
+	if rt.CheckedArithmetic && value == math.MinInt64 {
+		return newKindError(object.OVERFLOW_ERROR, "integer overflow: --%d", value)
+	}
 	return &object.Integer{Value: value - 1}
 }
 
 // evalInfixExpression 中缀表达式求值
-func evalInfixExpression(operator string, left, right object.Object) object.Object {
+func evalInfixExpression(operator string, left, right object.Object, rt *object.Runtime) object.Object {
 	switch {
+	case operator == "in":
+		return evalInExpression(left, right)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
-		return evalIntegerInfixExpression(operator, left, right)
+		return evalIntegerInfixExpression(operator, left, right, rt)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInfixExpression(operator, left, right, rt)
+	case operator == "*" && left.Type() == object.STRING_OBJ && right.Type() == object.INTEGER_OBJ:
+		return evalStringRepeat(left.(*object.String), right.(*object.Integer), rt)
+	case operator == "*" && left.Type() == object.INTEGER_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringRepeat(right.(*object.String), left.(*object.Integer), rt)
 	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
+		return nativeBoolToBooleanObject(objectsEqual(left, right))
 	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
-	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
-		return evalStringInfixExpression(operator, left, right)
+		return nativeBoolToBooleanObject(!objectsEqual(left, right))
 	case left.Type() != right.Type():
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return newKindError(object.TYPE_ERROR, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
 // evalIntegerInfixExpression 整型运算
-func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
+func evalIntegerInfixExpression(operator string, left, right object.Object, rt *object.Runtime) object.Object {
 	leftVal := left.(*object.Integer).Value
 	rightVal := right.(*object.Integer).Value
 
 	switch operator {
-	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
-	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
-	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
+	case "+", "-", "*":
+		result, overflow := integerArithmetic(operator, leftVal, rightVal)
+		if overflow && rt.CheckedArithmetic {
+			return newKindError(object.OVERFLOW_ERROR, "integer overflow: %d %s %d", leftVal, operator, rightVal)
+		}
+		return &object.Integer{Value: result}
 	case "/":
+		if rightVal == 0 {
+			return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %d / 0", leftVal)
+		}
+		if rt.CheckedArithmetic && leftVal == math.MinInt64 && rightVal == -1 {
+			return newKindError(object.OVERFLOW_ERROR, "integer overflow: %d / %d", leftVal, rightVal)
+		}
 		return &object.Integer{Value: leftVal / rightVal}
+	case "%":
+		if rightVal == 0 {
+			return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %d %% 0", leftVal)
+		}
+		return &object.Integer{Value: leftVal % rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
@@ -366,7 +467,25 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// integerArithmetic 执行加减乘运算，并报告结果是否超出 int64 范围
+func integerArithmetic(operator string, a, b int64) (int64, bool) {
+	switch operator {
+	case "+":
+		r := a + b
+		return r, (a > 0 && b > 0 && r < 0) || (a < 0 && b < 0 && r >= 0)
+	case "-":
+		r := a - b
+		return r, (a >= 0 && b < 0 && r < 0) || (a < 0 && b > 0 && r >= 0)
+	default:
+		r := a * b
+		if a == 0 || b == 0 {
+			return r, false
+		}
+		return r, r/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64)
 	}
 }
 
@@ -405,36 +524,101 @@ func evalForExpression(fs *ast.ForExpression, env *object.Environment) object.Ob
 	var result object.Object
 
 	if fs.Init != nil {
-		Eval(fs.Init, env)
+		if init := Eval(fs.Init, env); isError(init) {
+			return init
+		}
 	}
 	for {
-		if fs.Condition != nil {
-			condition := Eval(fs.Condition, env)
-			if isError(condition) {
-				return newError("condition must be present in for loop")
-			}
-			if !isTruthy(condition) {
-				// 跳出循环
-				break
-			}
-		} else {
+		// 循环回边处检查是否被取消
+		if err := checkContext(env.Runtime()); err != nil {
+			return err
+		}
+		if fs.Condition == nil {
 			return newError("condition must be present in for loop")
 		}
+		condition := Eval(fs.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			// 跳出循环
+			break
+		}
 
+		// 循环体中的 return 与错误（包括超出执行限制）直接向外传递
 		evaluated := Eval(fs.Body, env)
-		if isError(evaluated) {
-			return newError("for loop body error")
-		} else {
-			result = evaluated
+		if evaluated != nil {
+			if rt := evaluated.Type(); rt == object.RETURN_OBJ || rt == object.ERROR_OBJ {
+				return evaluated
+			}
 		}
+		result = evaluated
+
 		if fs.Post != nil {
-			Eval(fs.Post, env)
+			if post := Eval(fs.Post, env); isError(post) {
+				return post
+			}
 		}
 	}
 
 	return result
 }
 
+// evalForInExpression for-in 循环求值，每次迭代在新的块级作用域中绑定循环变量
+// 数组与字符串的键为下标，字符串按 UTF-8 字符迭代；哈希表按插入顺序迭代键值对
+func evalForInExpression(fi *ast.ForInExpression, env *object.Environment) object.Object {
+	iterable := Eval(fi.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	var pairs []object.HashPair
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for i, el := range iterable.Elements {
+			pairs = append(pairs, object.HashPair{Key: &object.Integer{Value: int64(i)}, Value: el})
+		}
+	case *object.String:
+		for i, ch := range []rune(iterable.Value) {
+			pairs = append(pairs, object.HashPair{Key: &object.Integer{Value: int64(i)}, Value: &object.String{Value: string(ch)}})
+		}
+	case *object.Hash:
+		for _, pair := range iterable.Items() {
+			// 只有一个循环变量时迭代哈希表的键
+			if fi.Key == nil {
+				pair.Value = pair.Key
+			}
+			pairs = append(pairs, pair)
+		}
+	default:
+		return newKindError(object.TYPE_ERROR, "cannot iterate over %s", iterable.Type())
+	}
+
+	var result object.Object
+	for _, pair := range pairs {
+		// 循环回边处检查是否被取消
+		if err := checkContext(env.Runtime()); err != nil {
+			return err
+		}
+
+		loopEnv := object.NewBlockEnvironment(env)
+		if fi.Key != nil {
+			loopEnv.Set(fi.Key.Value, pair.Key)
+		}
+		loopEnv.Set(fi.Value.Value, pair.Value)
+
+		evaluated := Eval(fi.Body, loopEnv)
+		if evaluated != nil {
+			if rt := evaluated.Type(); rt == object.RETURN_OBJ || rt == object.ERROR_OBJ {
+				return evaluated
+			}
+		}
+		result = evaluated
+	}
+
+	return result
+}
+
 // evalAssignStatement 赋值语句求值
 func evalAssignStatement(as *ast.AssignStatement, env *object.Environment) object.Object {
 	value := Eval(as.Value, env)
@@ -443,29 +627,86 @@ func evalAssignStatement(as *ast.AssignStatement, env *object.Environment) objec
 	}
 
 	name := as.Name.Value
-	if _, ok := env.Get(name); ok {
-		env.Set(name, value)
+	if env.Assign(name, value) {
 		return value
 	}
 
-	return newError("identifier not found: " + name)
+	return newKindError(object.NAME_ERROR, "identifier not found: "+name)
 }
 
-// evalStringInfixExpression 字符串拼接运算
-func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	if operator != "+" {
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
-	}
-
+// evalStringInfixExpression 字符串拼接与按字典序比较
+func evalStringInfixExpression(operator string, left, right object.Object, rt *object.Runtime) object.Object {
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
 
-	return &object.String{Value: leftVal + rightVal}
+	switch operator {
+	case "+":
+		if err := checkAlloc(rt, len(leftVal)+len(rightVal)); err != nil {
+			return err
+		}
+		return &object.String{Value: leftVal + rightVal}
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	default:
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalStringRepeat 字符串重复 "ab" * 3
+func evalStringRepeat(str *object.String, count *object.Integer, rt *object.Runtime) object.Object {
+	if count.Value < 0 {
+		return newError("negative repeat count: %d", count.Value)
+	}
+	if len(str.Value) > 0 && count.Value > int64(math.MaxInt32)/int64(len(str.Value)) {
+		return newKindError(object.OVERFLOW_ERROR, "string repeat too large: %d * %d", len(str.Value), count.Value)
+	}
+	if err := checkAlloc(rt, len(str.Value)*int(count.Value)); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.Repeat(str.Value, int(count.Value))}
+}
+
+// evalInExpression 包含运算：子串、数组元素或哈希表的键
+func evalInExpression(left, right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.String:
+		sub, ok := left.(*object.String)
+		if !ok {
+			return newKindError(object.TYPE_ERROR, "type mismatch: %s in STRING", left.Type())
+		}
+		return nativeBoolToBooleanObject(strings.Contains(right.Value, sub.Value))
+	case *object.Array:
+		for _, el := range right.Elements {
+			if objectsEqual(left, el) {
+				return TRUE
+			}
+		}
+		return FALSE
+	case *object.Hash:
+		key, ok := left.(object.Hashable)
+		if !ok {
+			return newKindError(object.KEY_ERROR, "unusable as hash key: %s", left.Type())
+		}
+		_, ok = right.Pairs[key.HashKey()]
+		return nativeBoolToBooleanObject(ok)
+	default:
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s in %s", left.Type(), right.Type())
+	}
 }
 
 // 错误处理
 func newError(format string, a ...interface{}) object.Object {
-	return &object.Error{Message: fmt.Sprintf(format, a...)}
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: object.RUNTIME_ERROR}
 }
 func isError(obj object.Object) bool {
 	// 判断是否为错误对象
@@ -475,6 +716,26 @@ func isError(obj object.Object) bool {
 	return false
 }
 
+// ApplyFunction 在运行时 rt 中以给定参数调用函数对象或内置函数，并捕获 Go 运行时 panic
+func ApplyFunction(fn object.Object, args []object.Object, rt *object.Runtime) (result object.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = newError("runtime panic: %v", r)
+		}
+	}()
+	return applyFunction(fn, args, rt)
+}
+
+// SafeEval 求值并捕获 Go 运行时 panic，保证脚本无法使宿主进程崩溃
+func SafeEval(node ast.Node, env *object.Environment) (result object.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = newError("runtime panic: %v", r)
+		}
+	}()
+	return Eval(node, env)
+}
+
 // 内置函数
 func evalBuiltin(node *ast.Identifier, env *object.Environment) object.Object {
 	if val, ok := env.Get(node.Value); ok {
@@ -485,5 +746,5 @@ func evalBuiltin(node *ast.Identifier, env *object.Environment) object.Object {
 		return builtin
 	}
 
-	return newError("identifier not found: " + node.Value)
+	return newKindError(object.NAME_ERROR, "identifier not found: "+node.Value)
 }
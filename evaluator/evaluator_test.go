@@ -4,7 +4,12 @@ import (
 	"Cmicro-Compiler/lexer"
 	"Cmicro-Compiler/object"
 	"Cmicro-Compiler/parser"
+	"bytes"
+	"context"
+	"math"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestClosures(t *testing.T) {
@@ -18,6 +23,242 @@ func TestClosures(t *testing.T) {
 	testIntegerObject(t, testEval(input), 4)
 }
 
+func TestPrefixWriteBack(t *testing.T) {
+	testIntegerObject(t, testEval("let i = 1; ++i; i"), 2)
+	testIntegerObject(t, testEval("let i = 1; --i; i"), 0)
+	testIntegerObject(t, testEval("let a = 1; -a; a"), 1)
+	if result := testEval("let b = true; !b; b"); result != TRUE {
+		t.Errorf("! must not write back. got=%s", result.Inspect())
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 / 0", "division by zero: 1 / 0"},
+		{"7 % 0", "division by zero: 7 % 0"},
+		{"let a = 0; 10 / a;", "division by zero: 10 / 0"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+	testIntegerObject(t, testEval("7 % 3"), 1)
+	testIntegerObject(t, testEval("2 + 7 % 4 * 2"), 8)
+}
+
+func TestCheckedArithmetic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "integer overflow: 9223372036854775807 + 1"},
+		{"0 - 9223372036854775807 - 2", "integer overflow: -9223372036854775807 - 2"},
+		{"4611686018427387904 * 2", "integer overflow: 4611686018427387904 * 2"},
+	}
+
+	for _, tt := range tests {
+		rt := object.NewRuntime()
+		rt.CheckedArithmetic = true
+		testErrorObject(t, testEvalWithRuntime(tt.input, rt), tt.expected)
+
+		// 未开启检查时保持回绕语义
+		if _, ok := testEval(tt.input).(*object.Integer); !ok {
+			t.Errorf("unchecked %q should wrap around", tt.input)
+		}
+	}
+}
+
+func TestSafeEvalRecoversPanic(t *testing.T) {
+	rt := object.NewRuntime()
+	rt.Builtins["boom"] = &object.Builtin{Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+		var elements []object.Object
+		return elements[len(args)]
+	}}
+	program := parser.New(lexer.New("let f = fn(a){ boom(a) }; f(1);")).ParseProgram()
+	result := SafeEval(program, object.NewEnvironmentWithRuntime(rt))
+
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("object is not Error. got=%T (%+v)", result, result)
+	}
+	if !strings.HasPrefix(errObj.Message, "runtime panic:") {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestFunctionArity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(a, b) { a + b }; f(1);", "ERROR: wrong number of arguments. got=1, want=2"},
+		{"let f = fn(a) { a }; f();", "ERROR: wrong number of arguments. got=0, want=1"},
+		{"let f = fn(a) { a }; f(1, 2);", "1"},
+		{`try { fn(a, b) { a }(1); } catch (e) { e["kind"] }`, "TypeError"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestBuiltinIO(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	rt := object.NewRuntime()
+	rt.SetInput(strings.NewReader("hello world\r\nsecond line"))
+	rt.Stdout = &stdout
+	rt.Stderr = &stderr
+
+	input := `
+	let a = input("name? ");
+	let b = input();
+	let c = input();
+	print(a, "|");
+	println(b);
+	eprintln("oops");
+	c;
+	`
+	testNullObject(t, testEvalWithRuntime(input, rt))
+
+	if stdout.String() != "name? hello world|second line\n" {
+		t.Errorf("wrong stdout. got=%q", stdout.String())
+	}
+	if stderr.String() != "oops\n" {
+		t.Errorf("wrong stderr. got=%q", stderr.String())
+	}
+	testErrorObject(t, testEvalWithRuntime("input(1)", rt), "argument to `input` must be STRING, got INTEGER")
+}
+
+func TestExecutionLimits(t *testing.T) {
+	tests := []struct {
+		input    string
+		setup    func(rt *object.Runtime)
+		kind     object.ErrorKind
+		expected string
+	}{
+		{
+			"for(let i = 0; i < 1; i){}",
+			func(rt *object.Runtime) { rt.MaxSteps = 1000 },
+			object.STEP_LIMIT_ERROR,
+			"step limit exceeded: 1000",
+		},
+		{
+			"let f = fn(n){ f(n + 1) }; f(0);",
+			func(rt *object.Runtime) { rt.MaxDepth = 50 },
+			object.DEPTH_LIMIT_ERROR,
+			"maximum call depth exceeded: 50",
+		},
+		{
+			"let f = fn(n){ f(n + 1) }; f(0);",
+			func(rt *object.Runtime) {},
+			object.DEPTH_LIMIT_ERROR,
+			"maximum call depth exceeded: 10000",
+		},
+		{
+			"let a = [1, 2, 3]; push(a, 4);",
+			func(rt *object.Runtime) { rt.MaxAlloc = 3 },
+			object.ALLOC_LIMIT_ERROR,
+			"allocation limit exceeded: 4 > 3",
+		},
+		{
+			`let s = "ab"; for(let i = 0; i < 10; ++i){ s = s + s; }`,
+			func(rt *object.Runtime) { rt.MaxAlloc = 100 },
+			object.ALLOC_LIMIT_ERROR,
+			"allocation limit exceeded: 128 > 100",
+		},
+	}
+
+	for _, tt := range tests {
+		rt := object.NewRuntime()
+		tt.setup(rt)
+		result := testEvalWithRuntime(tt.input, rt)
+		if !testErrorObject(t, result, tt.expected) {
+			continue
+		}
+		if kind := result.(*object.Error).Kind; kind != tt.kind {
+			t.Errorf("wrong error kind. got=%q, want=%q", kind, tt.kind)
+		}
+	}
+}
+
+func TestForReturnsFromFunction(t *testing.T) {
+	input := `
+	let find = fn(n){
+		for(let i = 0; i < 100; ++i){
+			if(i * i >= n){ return i; } else { i; }
+		}
+	};
+	find(50);
+	`
+	testIntegerObject(t, testEval(input), 8)
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let r = 0; try { r = 1 / 0; } catch (e) { r = e["kind"]; } r;`, "ZeroDivisionError"},
+		{`try { missing; } catch (e) { e["kind"] + ": " + e["message"] }`, "NameError: identifier not found: missing"},
+		{`try { 1 + "a"; } catch (e) { e["kind"] }`, "TypeError"},
+		{`try { {[1]: 2}; } catch (e) { e["kind"] }`, "KeyError"},
+		{`try { throw "boom"; } catch (e) { e["kind"] + ": " + e["message"] }`, "Error: boom"},
+		{`try { throw {"kind": "ValueError", "message": "bad"}; } catch (e) { e["kind"] + ": " + e["message"] }`, "ValueError: bad"},
+		{`try { throw 42; } catch (e) { e["value"] }`, 42},
+		{`try { throw {"kind": "TimeoutError", "message": "fake"}; } catch (e) { e["kind"] + ": " + e["message"] }`, "Error: fake"},
+		{`let log = ""; try { throw {"kind": "Exit"}; } catch (e) { log = e["kind"]; } finally { log = log + "f"; } log;`, "Errorf"},
+		{`let x = 1;
+try {
+  x + true;
+} catch (e) { e["line"] * 100 + e["column"] }`, 305},
+		{`let log = ""; try { log = log + "a"; } finally { log = log + "b"; } log;`, "ab"},
+		{`let log = ""; try { throw "x"; } catch (e) { log = log + "c"; } finally { log = log + "f"; } log;`, "cf"},
+		{`try { try { throw "inner"; } catch (e) { throw e; } } catch (e) { e["message"] }`, "inner"},
+		{`let f = fn(){ try { return 1; } finally { 2; } }; f();`, 1},
+		{`let f = fn(){ try { return 1; } finally { return 2; } }; f();`, 2},
+		{`try { throw "uncaught"; } finally { 1; }`, "uncaught"},
+		{`throw "plain";`, "plain"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestMemberAccess(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let h = {"x": 1, "double": fn(y) { y * 2 }}; h.x + h.double(3)`, 7},
+		{`let h = {"x": 1}; h.y`, nil},
+		{`[1, 2].push(3).len()`, 3},
+		{`[1, 2, 3].rest().first()`, 2},
+		{`"Cmicro".upper()`, "CMICRO"},
+		{`let lower = "AB".lower; lower()`, "ab"},
+		{`try { 1 / 0; } catch (e) { e.kind }`, "ZeroDivisionError"},
+		{`1.foo()`, "INTEGER has no method foo"},
+		{`[].push()`, "wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestLimitErrorsNotCaught(t *testing.T) {
+	rt := object.NewRuntime()
+	rt.MaxSteps = 500
+	result := testEvalWithRuntime(`try { for(let i = 0; i < 1; i){} } catch (e) { 1 }`, rt)
+	testErrorObject(t, result, "step limit exceeded: 500")
+}
+
 func testNullObject(t *testing.T, obj object.Object) bool {
 	if obj != NULL {
 		t.Errorf("object is not NULL. got=%T (%+v)", obj, obj)
@@ -26,11 +267,548 @@ func testNullObject(t *testing.T, obj object.Object) bool {
 	return true
 }
 
+func TestStructs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`struct Point { int x, int y }; Point{x: 1}.y`, 0},
+		{`struct Point { int x, y }; let p = Point{x: 1, y: 2}; p.x = p.x + p.y; p.x`, 3},
+		{`struct Node { int v, Node next }; let n = Node{v: 1, next: Node{v: 2}}; n.next.v + n.v`, 3},
+		{`struct Point { x, y }; let p = Point{x: 1}; p.y`, nil},
+		{`struct Point { x }; let p = Point{x: 1}; let move = fn(q) { q.x = 5; }; move(p); p.x`, 5},
+		{`struct Point { x }; Point{z: 1}`, "struct Point has no field z"},
+		{`struct Point { x }; Point{x: 1, x: 2}`, "duplicate field x in Point literal"},
+		{`struct Point { int x }; let p = Point{}; p.x = "a";`, "cannot use STRING value as int in field x of Point"},
+		{`struct Node { Node next }; Node{next: 1}`, "cannot use INTEGER value as Node in field next of Node"},
+		{`let Q = 1; Q{}`, "Q is not a struct"},
+		{`1.x = 2;`, "cannot assign to member of INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+
+	result := testEval(`struct Point { x, y }; Point{y: "b", x: 1}`)
+	if result.Inspect() != `Point{x: 1, y: b}` {
+		t.Errorf("wrong inspect. got=%q", result.Inspect())
+	}
+}
+
+func TestSlicing(t *testing.T) {
+	tests := []struct {
+		input    string
+		negative bool
+		strict   bool
+		expected string
+	}{
+		{`[1, 2, 3, 4][1:3]`, false, false, "[2, 3]"},
+		{`[1, 2, 3, 4][:2]`, false, false, "[1, 2]"},
+		{`[1, 2, 3, 4][2:]`, false, false, "[3, 4]"},
+		{`"hello"[1:3]`, false, false, "el"},
+		{`"héllo"[1:3]`, false, false, "él"},
+		{`"日本語"[-1]`, true, false, "語"},
+		{`"日本語"[3]`, false, true, "ERROR: index out of range: 3 (length 3)"},
+		{`[1, 2, 3][1:10]`, false, false, "[2, 3]"},
+		{`[1, 2, 3][2:1]`, false, false, "[]"},
+		{`[1, 2, 3][-1]`, false, false, "null"},
+		{`[1, 2, 3][-1]`, true, false, "3"},
+		{`"hello"[-3:]`, true, false, "llo"},
+		{`[1, 2, 3][:-1]`, true, false, "[1, 2]"},
+		{`[1, 2, 3][3]`, false, true, "ERROR: index out of range: 3 (length 3)"},
+		{`[1, 2, 3][-4]`, true, true, "ERROR: index out of range: -4 (length 3)"},
+		{`[1, 2, 3][1:10]`, false, true, "ERROR: slice bounds out of range: [1:10] (length 3)"},
+		{`1[1:2]`, false, false, "ERROR: slice operator not supported: INTEGER"},
+		{`[1][true:]`, false, false, "ERROR: slice index must be INTEGER, got BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		rt := object.NewRuntime()
+		rt.NegativeIndex = tt.negative
+		rt.StrictIndex = tt.strict
+		result := testEvalWithRuntime(tt.input, rt)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestStringOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abc"[0]`, "a"},
+		{`let s = "abc"; s[1] + s[2]`, "bc"},
+		{`"ab" * 3`, "ababab"},
+		{`2 * "x"`, "xx"},
+		{`"x" * -1`, "negative repeat count: -1"},
+		{`try { "ab" * 2000000000; } catch (e) { e["kind"] }`, "OverflowError"},
+		{`1 in "abc"`, "type mismatch: INTEGER in STRING"},
+		{`1 in 2`, "unknown operator: INTEGER in INTEGER"},
+		{`[1] in {}`, "unusable as hash key: ARRAY"},
+		{`"abc"[3]`, nil},
+		{`"é"[0] == "é"`, true},
+		{`len("héllo")`, 5},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+
+	comparisons := []struct {
+		input    string
+		expected bool
+	}{
+		{`"a" == "a"`, true},
+		{`"a" != "a"`, false},
+		{`"abc" < "abd"`, true},
+		{`"b" > "a"`, true},
+		{`"b" <= "a"`, false},
+		{`"a" >= "a"`, true},
+		{`3 <= 3`, true},
+		{`4 >= 5`, false},
+		{`"bc" in "abc"`, true},
+		{`2 in [1, 2]`, true},
+		{`"2" in [1, 2]`, false},
+		{`"a" in {"a": 1}`, true},
+		{`3 in {"a": 1}`, false},
+	}
+	for _, tt := range comparisons {
+		result := testEval(tt.input)
+		if result != nativeBoolToBooleanObject(tt.expected) {
+			t.Errorf("wrong result for %q. got=%s, want=%t", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestDeepEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`[1, 2] == [1, 2]`, true},
+		{`[1, 2] != [1, 2]`, false},
+		{`[1, [2, "a"]] == [1, [2, "a"]]`, true},
+		{`[1, 2] == [2, 1]`, false},
+		{`[1] == [1, 1]`, false},
+		{`{"a": 1, "b": [1]} == {"b": [1], "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{`{"a": 1} == {"b": 1}`, false},
+		{`[1] == {"a": 1}`, false},
+		{`1 == "1"`, false},
+		{`struct P { x, y }; P{x: 1, y: [2]} == P{x: 1, y: [2]}`, true},
+		{`struct P { x }; struct Q { x }; P{x: 1} == Q{x: 1}`, false},
+		{`let f = fn() {}; f == f`, true},
+		{`fn() {} == fn() {}`, false},
+		{`[1, 2] in [[1, 2]]`, true},
+		{`let a = {}; a.self = a; let b = {}; b.self = b; a == b`, true},
+		{`let a = {"v": 1}; a.self = a; let b = {"v": 2}; b.self = b; a == b`, false},
+		{`struct N { v, next }; let a = N{v: 1}; a.next = a; let b = N{v: 1}; b.next = N{v: 1, next: b}; a == b`, true},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result != nativeBoolToBooleanObject(tt.expected) {
+			t.Errorf("wrong result for %q. got=%s, want=%t", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+
+	result := testEval(`let a = {"v": [1]}; a.self = [a]; a.self`)
+	if result.Inspect() != `[{v: [1], self: [...]}]` {
+		t.Errorf("wrong inspect for cyclic value. got=%q", result.Inspect())
+	}
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`join(split("a,b,c", ","), "-")`, "a-b-c"},
+		{`len(split("abc", ""))`, 3},
+		{`len(split("中文", ""))`, 2},
+		{`"a,b".split(",").join("+")`, "a+b"},
+		{`join([1], "-")`, "elements of argument 1 to `join` must be STRING, got INTEGER at index 0"},
+		{`trim("  x ")`, "x"},
+		{`trim("xxhixx", "x")`, "hi"},
+		{`upper("abc") + lower("DEF")`, "ABCdef"},
+		{`replace("aaa", "a", "b")`, "bbb"},
+		{`index_of("abc", "c")`, 2},
+		{`index_of("abc", "d")`, -1},
+		{`index_of("日本語", "語")`, 2},
+		{`substr("日本語", 1, 1)`, "本"},
+		{`substr("hello", 1, 3)`, "ell"},
+		{`substr("hello", 2)`, "llo"},
+		{`substr("hello", 3, 10)`, "lo"},
+		{`substr("abc", 1, 9223372036854775807)`, "bc"},
+		{`substr("hello", 9)`, "start index out of range: 9 (length 5)"},
+		{`repeat("ab", 2)`, "abab"},
+		{`format("%s is %d, %q %%", "bob", 3, "x")`, `bob is 3, "x" %`},
+		{`"[%v]".format([1, 2])`, "[[1, 2]]"},
+		{`sprintf("%d", "a")`, "`sprintf`: %d requires INTEGER, got STRING"},
+		{`format("%s")`, "`format`: missing argument for %s"},
+		{`format("x", 1)`, "`format`: too many arguments. got=1, want=0"},
+		{`format("%x", 1)`, "`format`: unknown verb %x"},
+		{`split(1, ",")`, "argument 1 to `split` must be STRING, got INTEGER"},
+		{`upper(1)`, "argument to `upper` must be STRING, got INTEGER"},
+		{`replace("a", "b")`, "wrong number of arguments. got=2, want=3"},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+
+	predicates := []struct {
+		input    string
+		expected bool
+	}{
+		{`contains("abc", "b")`, true},
+		{`"abc".contains("d")`, false},
+		{`starts_with("abc", "ab")`, true},
+		{`ends_with("abc", "ab")`, false},
+	}
+	for _, tt := range predicates {
+		result := testEval(tt.input)
+		if result != nativeBoolToBooleanObject(tt.expected) {
+			t.Errorf("wrong result for %q. got=%s, want=%t", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestArrayBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`map([1, 2, 3], fn(x) { x * 2 })`, "[2, 4, 6]"},
+		{`map(["a", "b"], upper)`, "[A, B]"},
+		{`filter(range(10), fn(x) { x % 3 == 0 })`, "[0, 3, 6, 9]"},
+		{`reduce([1, 2, 3, 4], fn(acc, x) { acc + x })`, "10"},
+		{`reduce([], fn(acc, x) { acc + x }, 0)`, "0"},
+		{`reduce([], fn(acc, x) { acc + x })`, "ERROR: `reduce` of empty array with no initial value"},
+		{`sort([3, 1, 2])`, "[1, 2, 3]"},
+		{`sort(["b", "c", "a"])`, "[a, b, c]"},
+		{`sort([3, 1, 2], fn(a, b) { b - a })`, "[3, 2, 1]"},
+		{`sort(["bb", "a", "ccc"], fn(a, b) { len(a) < len(b) })`, "[a, bb, ccc]"},
+		{`sort([1, "a"])`, "ERROR: `sort` without comparator needs all INTEGER or all STRING elements, got STRING"},
+		{`sort([2, 1], fn(a, b) { "x" })`, "ERROR: `sort` comparator must return INTEGER or BOOLEAN, got STRING"},
+		{`reverse([1, 2, 3])`, "[3, 2, 1]"},
+		{`range(3)`, "[0, 1, 2]"},
+		{`range(2, 5)`, "[2, 3, 4]"},
+		{`range(5, 0, -2)`, "[5, 3, 1]"},
+		{`range(0, 1, 0)`, "ERROR: `range` step must not be zero"},
+		{`try { range(100000000); } catch (e) { e["kind"] }`, "OverflowError"},
+		{`zip([1, 2, 3], ["a", "b"])`, "[[1, a], [2, b]]"},
+		{`any([1, 2, 3], fn(x) { x > 2 })`, "true"},
+		{`all([1, 2, 3], fn(x) { x > 2 })`, "false"},
+		{`all([])`, "true"},
+		{`any([false, 1 > 2])`, "false"},
+		{`index_of([1, [2], 3], [2])`, "1"},
+		{`contains([1, 2], 3)`, "false"},
+		{`[3, 1, 2].sort().map(fn(x) { x + 1 }).reverse()`, "[4, 3, 2]"},
+		{`map([1], fn(x) { x / 0 })`, "ERROR: division by zero: 1 / 0"},
+		{`map([1], fn(a, b) { a })`, "ERROR: wrong number of arguments. got=1, want=2"},
+		{`map(1, fn(x) { x })`, "ERROR: argument 1 to `map` must be ARRAY, got INTEGER"},
+		{`filter([1], 2)`, "ERROR: argument 2 to `filter` must be FUNCTION, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestArrayBuiltinsCheckAlloc(t *testing.T) {
+	inputs := []string{
+		`map(big, fn(x) { x })`,
+		`filter(big, fn(x) { true })`,
+		`reverse(big)`,
+		`zip(big, big)`,
+		`sort(big)`,
+		`sort(big, fn(a, b) { a - b })`,
+	}
+
+	for _, input := range inputs {
+		rt := object.NewRuntime()
+		env := object.NewEnvironmentWithRuntime(rt)
+		env.Set("big", &object.Array{Elements: []object.Object{
+			&object.Integer{Value: 1}, &object.Integer{Value: 2}, &object.Integer{Value: 3}, &object.Integer{Value: 4},
+		}})
+		rt.MaxAlloc = 3
+		result := Eval(parser.New(lexer.New(input)).ParseProgram(), env)
+		if !testErrorObject(t, result, "allocation limit exceeded: 4 > 3") {
+			continue
+		}
+		if kind := result.(*object.Error).Kind; kind != object.ALLOC_LIMIT_ERROR {
+			t.Errorf("wrong error kind for %q. got=%q", input, kind)
+		}
+	}
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`abs(-3) + abs(4)`, "7"},
+		{`[min(3, 1, 2), max(3, 1, 2), min([5, 4]), max([7])]`, "[1, 3, 4, 7]"},
+		{`min([])`, "ERROR: `min` of empty array"},
+		{`max(1, "a")`, "ERROR: argument 2 to `max` must be INTEGER, got STRING"},
+		{`max([1, "a"])`, "ERROR: element 1 of `max` must be INTEGER, got STRING"},
+		{`[pow(2, 10), pow(-2, 3), pow(5, 0)]`, "[1024, -8, 1]"},
+		{`pow(2, -1)`, "ERROR: negative exponent: -1"},
+		{`[sqrt(0), sqrt(15), sqrt(16), sqrt(9223372036854775807)]`, "[0, 3, 4, 3037000499]"},
+		{`sqrt(-1)`, "ERROR: square root of negative number: -1"},
+		{`[floor(7, 2), floor(-7, 2), ceil(7, 2), ceil(-7, 2), floor(5)]`, "[3, -4, 4, -3, 5]"},
+		{`[round(5, 2), round(-5, 2), round(4, 3), round(-7, 3), round(7, -2)]`, "[3, -3, 1, -2, -4]"},
+		{`floor(1, 0)`, "ERROR: division by zero: floor(1, 0)"},
+		{`[clamp(5, 0, 3), clamp(-1, 0, 3), clamp(2, 0, 3)]`, "[3, 0, 2]"},
+		{`clamp(1, 3, 0)`, "ERROR: `clamp` lower bound 3 is greater than upper bound 0"},
+		{`let r = rand(10); [r >= 0, r < 10]`, "[true, true]"},
+		{`let r = rand_int(-2, 2); [r >= -2, r <= 2]`, "[true, true]"},
+		{`rand_int(3, 3)`, "3"},
+		{`rand(0)`, "ERROR: argument to `rand` must be positive, got 0"},
+		{`rand_seed(42); let a = [rand(), rand_int(1, 6)]; rand_seed(42); a == [rand(), rand_int(1, 6)]`, "true"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+
+	rt := object.NewRuntime()
+	rt.CheckedArithmetic = true
+	for _, input := range []string{`abs(-9223372036854775807 - 1)`, `pow(2, 63)`, `pow(3, 100)`} {
+		result := testEvalWithRuntime(input, rt)
+		if errObj, ok := result.(*object.Error); !ok || errObj.Kind != object.OVERFLOW_ERROR {
+			t.Errorf("expected overflow error for %q, got=%s", input, result.Inspect())
+		}
+	}
+	testIntegerObject(t, testEvalWithRuntime(`pow(-2, 63)`, rt), math.MinInt64)
+}
+
+func TestTypeBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[type(1), type("a"), type(true), type([]), type({}), type(len), type(fn() {})]`,
+			"[INTEGER, STRING, BOOLEAN, ARRAY, HASH, BUILTIN, FUNCTION]"},
+		{`struct P { x }; [type(P{x: 1}), type(P)]`, "[STRUCT, STRUCT_TYPE]"},
+		{`str(12) + "!"`, "12!"},
+		{`str([1, "a"])`, "[1, a]"},
+		{`int("42") + 1`, "43"},
+		{`int("-7")`, "-7"},
+		{`[int(true), int(false), int(5)]`, "[1, 0, 5]"},
+		{`int("4x")`, `ERROR: invalid integer: "4x"`},
+		{`int("99999999999999999999")`, `ERROR: integer out of range: "99999999999999999999"`},
+		{`int([1])`, "ERROR: cannot convert ARRAY to INTEGER"},
+		{`[bool(0), bool(""), bool(1 > 2), bool(first([]))]`, "[true, true, false, false]"},
+		{`[is_int(1), is_string(1), is_array([]), is_hash({}), is_null(first([]))]`, "[true, false, true, true, true]"},
+		{`struct P { x }; [is_function(len), is_function(fn() {}), is_struct(P{x: 1}), is_bool(true)]`, "[true, true, true, true]"},
+		{`type()`, "ERROR: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestJSONBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json_parse("[1, [true], {}]")`, "[1, [true], {}]"},
+		{`json_parse("[1, 2")`, "ERROR: invalid json: unexpected end of JSON input"},
+		{`json_parse("")`, "ERROR: invalid json: unexpected end of JSON input"},
+		{`json_parse("1.5")`, "ERROR: invalid json: number 1.5 is not a 64-bit integer"},
+		{`json_parse("1 2")`, "ERROR: invalid json: unexpected data after top-level value"},
+		{`json_stringify({"b": [1, "<q>"], "a": first([]), "t": true})`, `{"b":[1,"<q>"],"a":null,"t":true}`},
+		{`json_stringify({"a": [1]}, 2)`, "{\n  \"a\": [\n    1\n  ]\n}"},
+		{`struct P { x, y }; json_stringify(P{x: 1, y: "s"})`, `{"x":1,"y":"s"}`},
+		{`let h = {"n": 1}; json_stringify(json_parse(json_stringify([h, h]))) `, `[{"n":1},{"n":1}]`},
+		{`json_stringify({1: 2})`, "ERROR: json object keys must be STRING, got INTEGER"},
+		{`json_stringify([fn(x) { x }])`, "ERROR: cannot convert FUNCTION to json"},
+		{`let a = [1]; let h = {"a": a}; h.self = h; json_stringify(h)`, "ERROR: cannot convert cyclic value to json"},
+		{`json_stringify(1, 11)`, "ERROR: json indent must be between 0 and 10, got 11"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+// fakeClock 测试用时钟，sleep 只推进时间而不真正等待
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Monotonic() time.Duration {
+	return c.now.Sub(time.Unix(0, 0))
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+func TestTimeBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`now()`, "1700000000000"},
+		{`let start = clock(); sleep(1500); [clock() - start, now()]`, "[1500, 1700000001500]"},
+		{`format_time(now(), "2006-01-02 15:04:05", "UTC")`, "2023-11-14 22:13:20"},
+		{`format_time(0, "2006", "Nowhere/City")`, "ERROR: unknown time zone: Nowhere/City"},
+		{`sleep(-1)`, "ERROR: sleep duration must not be negative, got -1"},
+		{`now(1)`, "ERROR: wrong number of arguments. got=1, want=0"},
+	}
+
+	for _, tt := range tests {
+		rt := object.NewRuntime()
+		rt.Clock = &fakeClock{now: time.UnixMilli(1700000000000)}
+		result := testEvalWithRuntime(tt.input, rt)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+
+	// 系统时钟的 sleep 在执行被取消时立即返回
+	rt := object.NewRuntime()
+	ctx, cancel := context.WithCancel(context.Background())
+	rt.Context = ctx
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	result := testEvalWithRuntime(`sleep(60000)`, rt)
+	if errObj, ok := result.(*object.Error); !ok || errObj.Kind != object.CANCELLED_ERROR {
+		t.Errorf("expected cancelled error, got=%s", result.Inspect())
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("sleep was not interrupted, took %s", elapsed)
+	}
+}
+
+func TestRegexpBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[re_match("abc", "^a.c$"), re_match("abcd", "^a.c$")]`, "[true, false]"},
+		{`re_find_all("a1 b22 c333", "\d+")`, "[1, 22, 333]"},
+		{`re_find_all("a=1, b=2", "(\w)=(\d)")`, "[[a=1, a, 1], [b=2, b, 2]]"},
+		{`re_find_all("abc", "x")`, "[]"},
+		{`re_replace("me@host", "(\w+)@(\w+)", "$2 at ${1}")`, "host at me"},
+		{`re_split("a , b,c", "\s*,\s*")`, "[a, b, c]"},
+		{`"a1b2".re_find_all("\d")`, "[1, 2]"},
+		{`"x-y".re_replace("-", "+").re_split("\+")`, "[x, y]"},
+		{`"abc".re_match("b")`, "true"},
+		{`re_match("a", "(a")`, "ERROR: invalid regular expression: missing closing ): `(a`"},
+		{`re_match(1, "a")`, "ERROR: argument 1 to `re_match` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+
+	rt := object.NewRuntime()
+	testEvalWithRuntime(`re_match("aa", "a+"); re_match("b", "a+"); re_split("abc", "b");`, rt)
+	if len(rt.RegexpCache) != 2 {
+		t.Errorf("expected 2 cached patterns, got=%d", len(rt.RegexpCache))
+	}
+}
+
+func TestForIn(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let sum = 0; for (x in [1, 2, 3]) { sum = sum + x; } sum`, "6"},
+		{`let out = []; for (i, x in ["a", "b"]) { out = push(out, i); out = push(out, x); } out`, "[0, a, 1, b]"},
+		{`let out = ""; for (k, v in {"b": 1, "a": 2}) { out = out + format("%s%d", k, v); } out`, "b1a2"},
+		{`let out = []; for (k in {"b": 1, "a": 2}) { out = push(out, k); } out`, "[b, a]"},
+		{`let out = ""; for (ch in "abc") { out = ch + out; } out`, "cba"},
+		{`let n = 0; for (i, ch in "ab") { n = n + i; } n`, "1"},
+		{`let out = ""; for (i, ch in "a中b") { out = out + str(i) + ch; } out`, "0a1中2b"},
+		{`let x = 10; for (x in [1, 2]) { let y = x; } x`, "10"},
+		{`for (x in [1]) { let y = x; } y`, "ERROR: identifier not found: y"},
+		{`let fs = []; for (x in [1, 2]) { fs = push(fs, fn() { x }); } fs[0]() + fs[1]()`, "3"},
+		{`let y = 5; for (x in [1]) { map([1], fn(a) { a + y }) }`, "[6]"},
+		{`let g = fn(a) { a * 2 }; for (x in [1]) { let f = fn() { g(x) }; f() }`, "2"},
+		{`let desc = true; for (xs in [[1, 3, 2]]) { sort(xs, fn(a, b) { if (desc) { b - a } else { a - b } }) }`, "[3, 2, 1]"},
+		{`for (x in [1]) { for (y in [2]) { let f = fn() { x + y }; f() } }`, "3"},
+		{`let f = fn() { for (x in [1, 2, 3]) { if (x == 2) { return x * 10; } else { x } } }; f()`, "20"},
+		{`for (x in 1) { x }`, "ERROR: cannot iterate over INTEGER"},
+		{`for (x in [1, 0]) { 1 / x }`, "ERROR: division by zero: 1 / 0"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestHashBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"b": 1, "a": 2, 3: 3}`, "{b: 1, a: 2, 3: 3}"},
+		{`keys({"b": 1, "a": 2})`, "[b, a]"},
+		{`values({"b": 1, "a": 2})`, "[1, 2]"},
+		{`items({"b": 1, "a": 2})`, "[[b, 1], [a, 2]]"},
+		{`let h = {"a": 1, "b": 2}; h.a = 3; h.c = 4; h`, "{a: 3, b: 2, c: 4}"},
+		{`has({"a": 1}, "a")`, "true"},
+		{`{"a": 1}.has("b")`, "false"},
+		{`has({"a": 1}, [1])`, "ERROR: unusable as hash key: ARRAY"},
+		{`let h = {"a": 1, "b": 2}; [delete(h, "a"), delete(h, "a"), h]`, "[true, false, {b: 2}]"},
+		{`let h = {"a": 1, "b": 2}; delete(h, "a"); h.a = 1; keys(h)`, "[b, a]"},
+		{`let h = {"a": 1, "b": 2, "c": 3, "d": 4}; delete(h, "b"); delete(h, "a"); h.b = 5; delete(h, "d"); h.e = 6; keys(h)`, "[c, b, e]"},
+		{`merge({"a": 1, "b": 2}, {"c": 3, "a": 4})`, "{a: 4, b: 2, c: 3}"},
+		{`let h = {"a": 1}; merge(h, {"b": 2}); h`, "{a: 1}"},
+		{`merge({"a": 1})`, "ERROR: wrong number of arguments. got=1, want>=2"},
+		{`merge({}, 1)`, "ERROR: argument 2 to `merge` must be HASH, got INTEGER"},
+		{`len({"a": 1, "b": 2})`, "2"},
+		{`{"a": 1}.keys().len()`, "1"},
+		{`keys([1])`, "ERROR: argument to `keys` must be HASH, got ARRAY"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(tt.input)
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
 func testEval(input string) object.Object {
+	return testEvalWithRuntime(input, object.NewRuntime())
+}
+
+func testEvalWithRuntime(input string, rt *object.Runtime) object.Object {
 	l := lexer.New(input)
 	p := parser.New(l)
 	program := p.ParseProgram()
-	env := object.NewEnvironment()
+	env := object.NewEnvironmentWithRuntime(rt)
 
 	return Eval(program, env)
 }
@@ -62,3 +840,42 @@ func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
 
 	return true
 }
+
+// testResult 比较求值结果：int 对应整数，string 对应字符串或错误信息，nil 对应 NULL
+func testResult(t *testing.T, input string, result object.Object, expected interface{}) {
+	switch expected := expected.(type) {
+	case int:
+		testIntegerObject(t, result, int64(expected))
+	case nil:
+		if result != NULL {
+			t.Errorf("wrong result for %q. got=%T (%+v), want NULL", input, result, result)
+		}
+	case string:
+		switch result := result.(type) {
+		case *object.String:
+			if result.Value != expected {
+				t.Errorf("wrong result for %q. got=%q, want=%q", input, result.Value, expected)
+			}
+		case *object.Error:
+			if result.Message != expected {
+				t.Errorf("wrong error for %q. got=%q, want=%q", input, result.Message, expected)
+			}
+		default:
+			t.Errorf("unexpected result for %q. got=%T (%+v)", input, result, result)
+		}
+	}
+}
+
+func testErrorObject(t *testing.T, obj object.Object, expected string) bool {
+	errObj, ok := obj.(*object.Error)
+	if !ok {
+		t.Errorf("object is not Error. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. got=%q, want=%q", errObj.Message, expected)
+		return false
+	}
+
+	return true
+}
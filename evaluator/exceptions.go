@@ -0,0 +1,142 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/ast"
+	"Cmicro-Compiler/object"
+	"Cmicro-Compiler/token"
+)
+
+/**
+ * @Description: throw 与 try/catch/finally 求值
+ */
+
+// evalThrowStatement 将抛出的值转换为错误
+// 字符串作为错误信息；包含 message、kind 的哈希表可指定错误类别；捕获到的错误原样重新抛出
+func evalThrowStatement(ts *ast.ThrowStatement, env *object.Environment) object.Object {
+	val := Eval(ts.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	switch val := val.(type) {
+	case *object.Exception:
+		return val.Error
+	case *object.String:
+		return &object.Error{Message: val.Value, Kind: object.THROWN_ERROR, Value: val}
+	case *object.Hash:
+		errObj := &object.Error{Message: val.Inspect(), Kind: object.THROWN_ERROR, Value: val}
+		if message, ok := hashStringField(val, "message"); ok {
+			errObj.Message = message
+		}
+		// 保留类别仍按 THROWN_ERROR 处理，避免脚本伪造不可捕获的错误
+		if kind, ok := hashStringField(val, "kind"); ok && !isLimitKind(object.ErrorKind(kind)) {
+			errObj.Kind = object.ErrorKind(kind)
+		}
+		return errObj
+	default:
+		return &object.Error{Message: val.Inspect(), Kind: object.THROWN_ERROR, Value: val}
+	}
+}
+
+// hashStringField 读取哈希表中字符串键对应的字符串值
+func hashStringField(hash *object.Hash, key string) (string, bool) {
+	pair, ok := hash.Pairs[(&object.String{Value: key}).HashKey()]
+	if !ok {
+		return "", false
+	}
+	str, ok := pair.Value.(*object.String)
+	if !ok {
+		return "", false
+	}
+	return str.Value, true
+}
+
+// evalTryStatement 执行 try 块，捕获错误后执行 catch 块，最后总会执行 finally 块
+// 执行限制类错误不会被捕获，也不会执行 finally 块
+func evalTryStatement(ts *ast.TryStatement, env *object.Environment) object.Object {
+	result := Eval(ts.Block, env)
+
+	if errObj, ok := result.(*object.Error); ok && ts.Catch != nil && !isLimitError(errObj) {
+		env.Set(ts.CatchParam.Value, &object.Exception{Error: errObj})
+		result = Eval(ts.Catch, env)
+	}
+
+	if errObj, ok := result.(*object.Error); ok && isLimitError(errObj) {
+		return result
+	}
+
+	if ts.Finally != nil {
+		// finally 块中的错误或 return 会覆盖之前的结果
+		finally := Eval(ts.Finally, env)
+		if finally != nil {
+			if rt := finally.Type(); rt == object.RETURN_OBJ || rt == object.ERROR_OBJ {
+				return finally
+			}
+		}
+	}
+	return result
+}
+
+// evalExceptionField 读取被捕获错误的字段
+func evalExceptionField(ex *object.Exception, name string) object.Object {
+	switch name {
+	case "message":
+		return &object.String{Value: ex.Error.Message}
+	case "kind":
+		return &object.String{Value: string(ex.Error.ErrorKind())}
+	case "line":
+		return &object.Integer{Value: int64(ex.Error.Line)}
+	case "column":
+		return &object.Integer{Value: int64(ex.Error.Column)}
+	case "value":
+		if ex.Error.Value != nil {
+			return ex.Error.Value
+		}
+		return NULL
+	default:
+		return NULL
+	}
+}
+
+// nodeToken 返回可能产生错误的节点所对应的 token，用于记录错误位置
+func nodeToken(node ast.Node) (token.Token, bool) {
+	switch node := node.(type) {
+	case *ast.Identifier:
+		return node.Token, true
+	case *ast.PrefixExpression:
+		return node.Token, true
+	case *ast.InfixExpression:
+		return node.Token, true
+	case *ast.CallExpression:
+		return node.Token, true
+	case *ast.IndexExpression:
+		return node.Token, true
+	case *ast.SliceExpression:
+		return node.Token, true
+	case *ast.MemberExpression:
+		return node.Token, true
+	case *ast.HashLiteral:
+		return node.Token, true
+	case *ast.StructLiteral:
+		return node.Token, true
+	case *ast.StructStatement:
+		return node.Token, true
+	case *ast.MemberAssignStatement:
+		return node.Target.Token, true
+	case *ast.ArrayLiteral:
+		return node.Token, true
+	case *ast.AssignStatement:
+		return node.Token, true
+	case *ast.ImportStatement:
+		return node.Token, true
+	case *ast.ThrowStatement:
+		return node.Token, true
+	case *ast.IfExpression:
+		return node.Token, true
+	case *ast.ForExpression:
+		return node.Token, true
+	case *ast.ForInExpression:
+		return node.Token, true
+	}
+	return token.Token{}, false
+}
@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+/**
+ * @Description: 文件系统内置函数，相对路径以进程的工作目录为基准；运行时禁用文件系统时返回 PermissionError
+ */
+
+var fileBuiltins = map[string]*object.Builtin{
+	"read_file": fileBuiltin("read_file", func(rt *object.Runtime, args []object.Object) object.Object {
+		if err := checkArgs("read_file", args, object.STRING_OBJ); err != nil {
+			return err
+		}
+		f, err := os.Open(stringArg(args[0]))
+		if err != nil {
+			return ioError(err)
+		}
+		defer f.Close()
+
+		//设置了 MaxAlloc 时先按文件大小检查，读取时最多多读一个字节，避免超大文件整体读入内存
+		var r io.Reader = f
+		if rt.MaxAlloc > 0 {
+			if info, err := f.Stat(); err == nil {
+				if err := checkAlloc(rt, int(info.Size())); err != nil {
+					return err
+				}
+			}
+			r = io.LimitReader(f, int64(rt.MaxAlloc)+1)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return ioError(err)
+		}
+		if err := checkAlloc(rt, len(data)); err != nil {
+			return err
+		}
+		return &object.String{Value: string(data)}
+	}),
+	"write_file": fileBuiltin("write_file", func(rt *object.Runtime, args []object.Object) object.Object {
+		if err := checkArgs("write_file", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+			return err
+		}
+		if err := os.WriteFile(stringArg(args[0]), []byte(stringArg(args[1])), 0o644); err != nil {
+			return ioError(err)
+		}
+		return NULL
+	}),
+	"append_file": fileBuiltin("append_file", func(rt *object.Runtime, args []object.Object) object.Object {
+		if err := checkArgs("append_file", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(stringArg(args[0]), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return ioError(err)
+		}
+		_, err = f.WriteString(stringArg(args[1]))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return ioError(err)
+		}
+		return NULL
+	}),
+	"exists": fileBuiltin("exists", func(rt *object.Runtime, args []object.Object) object.Object {
+		if err := checkArgs("exists", args, object.STRING_OBJ); err != nil {
+			return err
+		}
+		_, err := os.Stat(stringArg(args[0]))
+		if errors.Is(err, fs.ErrNotExist) {
+			return FALSE
+		}
+		if err != nil {
+			return ioError(err)
+		}
+		return TRUE
+	}),
+	"list_dir": fileBuiltin("list_dir", func(rt *object.Runtime, args []object.Object) object.Object {
+		if err := checkArgs("list_dir", args, object.STRING_OBJ); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(stringArg(args[0]))
+		if err != nil {
+			return ioError(err)
+		}
+		if err := checkAlloc(rt, len(entries)); err != nil {
+			return err
+		}
+		// os.ReadDir 按文件名排序返回
+		names := make([]object.Object, len(entries))
+		for i, entry := range entries {
+			names[i] = &object.String{Value: entry.Name()}
+		}
+		return &object.Array{Elements: names}
+	}),
+	"remove": fileBuiltin("remove", func(rt *object.Runtime, args []object.Object) object.Object {
+		if err := checkArgs("remove", args, object.STRING_OBJ); err != nil {
+			return err
+		}
+		if err := os.Remove(stringArg(args[0])); err != nil {
+			return ioError(err)
+		}
+		return NULL
+	}),
+}
+
+// fileBuiltin 创建访问文件系统的内置函数，调用前检查运行时是否允许访问文件系统
+func fileBuiltin(name string, fn func(rt *object.Runtime, args []object.Object) object.Object) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if !rt.FileSystem {
+				return newKindError(object.PERMISSION_ERROR, "file system access is disabled: %s", name)
+			}
+			return fn(rt, args)
+		},
+	}
+}
+
+// ioError 将文件操作的错误转换为 IOError
+func ioError(err error) object.Object {
+	return newKindError(object.IO_ERROR, "%s", err.Error())
+}
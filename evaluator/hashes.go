@@ -0,0 +1,102 @@
+package evaluator
+
+import "Cmicro-Compiler/object"
+
+/**
+ * @Description: 哈希表内置函数，结果均按键的插入顺序排列
+ */
+
+var hashBuiltins = map[string]*object.Builtin{
+	"keys": { //所有键组成的数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("keys", args, object.HASH_OBJ); err != nil {
+				return err
+			}
+			return mapItems(args[0].(*object.Hash), func(pair object.HashPair) object.Object {
+				return pair.Key
+			})
+		},
+	},
+	"values": { //所有值组成的数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("values", args, object.HASH_OBJ); err != nil {
+				return err
+			}
+			return mapItems(args[0].(*object.Hash), func(pair object.HashPair) object.Object {
+				return pair.Value
+			})
+		},
+	},
+	"items": { //所有 [键, 值] 组成的数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("items", args, object.HASH_OBJ); err != nil {
+				return err
+			}
+			return mapItems(args[0].(*object.Hash), func(pair object.HashPair) object.Object {
+				return &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+			})
+		},
+	},
+	"has": { //判断键是否存在
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkHashArgs("has", args); err != nil {
+				return err
+			}
+			return evalInExpression(args[1], args[0])
+		},
+	},
+	"delete": { //删除键，返回键是否存在；哈希表在原处修改
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkHashArgs("delete", args); err != nil {
+				return err
+			}
+			key, ok := args[1].(object.Hashable)
+			if !ok {
+				return newKindError(object.KEY_ERROR, "unusable as hash key: %s", args[1].Type())
+			}
+			return nativeBoolToBooleanObject(args[0].(*object.Hash).Delete(key.HashKey()))
+		},
+	},
+	"merge": { //合并多个哈希表为新的哈希表，相同的键取后面的值
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) < 2 {
+				return newError("wrong number of arguments. got=%d, want>=2", len(args))
+			}
+			merged := object.NewHash()
+			for i, arg := range args {
+				hash, ok := arg.(*object.Hash)
+				if !ok {
+					return newError("argument %d to `merge` must be HASH, got %s", i+1, arg.Type())
+				}
+				for _, pair := range hash.Items() {
+					merged.Set(pair.Key.(object.Hashable).HashKey(), pair)
+				}
+			}
+			if err := checkAlloc(rt, len(merged.Pairs)); err != nil {
+				return err
+			}
+			return merged
+		},
+	},
+}
+
+// mapItems 按插入顺序将每个键值对转换为数组元素
+func mapItems(hash *object.Hash, f func(object.HashPair) object.Object) object.Object {
+	items := hash.Items()
+	elements := make([]object.Object, len(items))
+	for i, pair := range items {
+		elements[i] = f(pair)
+	}
+	return &object.Array{Elements: elements}
+}
+
+// checkHashArgs 校验 (哈希表, 键) 形式的参数
+func checkHashArgs(name string, args []object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.HASH_OBJ {
+		return newError("argument 1 to `%s` must be HASH, got %s", name, args[0].Type())
+	}
+	return nil
+}
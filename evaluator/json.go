@@ -0,0 +1,231 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/**
+ * @Description: JSON 与对象之间的转换，对象的键保持 JSON 文本中的顺序
+ */
+
+// json_stringify 允许的最大缩进空格数
+const maxJSONIndent = 10
+
+var jsonBuiltins = map[string]*object.Builtin{
+	"json_parse": { //将 JSON 文本解析为对象，数字必须是整数
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("json_parse", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			obj, err := ParseJSON(stringArg(args[0]))
+			if err != nil {
+				return newError("%s", err.Error())
+			}
+			return obj
+		},
+	},
+	"json_stringify": { //将对象序列化为 JSON 文本，可选参数为缩进的空格数
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			indent := int64(0)
+			switch len(args) {
+			case 1:
+			case 2:
+				if args[1].Type() != object.INTEGER_OBJ {
+					return newError("argument 2 to `json_stringify` must be INTEGER, got %s", args[1].Type())
+				}
+				indent = intArg(args[1])
+			default:
+				return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+			}
+			if indent < 0 || indent > maxJSONIndent {
+				return newError("json indent must be between 0 and %d, got %d", maxJSONIndent, indent)
+			}
+
+			s, err := StringifyJSON(args[0], int(indent))
+			if err != nil {
+				return newError("%s", err.Error())
+			}
+			if err := checkAlloc(rt, len(s)); err != nil {
+				return err
+			}
+			return &object.String{Value: s}
+		},
+	},
+}
+
+// ParseJSON 将 JSON 文本解析为对象，对象转换为按键的出现顺序插入的哈希表
+func ParseJSON(src string) (object.Object, error) {
+	dec := json.NewDecoder(strings.NewReader(src))
+	dec.UseNumber()
+
+	obj, err := decodeJSON(dec)
+	if err == nil {
+		if _, extra := dec.Token(); extra != io.EOF {
+			err = errors.New("unexpected data after top-level value")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	return obj, nil
+}
+
+// decodeJSON 从解码器中读取一个完整的 JSON 值
+func decodeJSON(dec *json.Decoder) (object.Object, error) {
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return nil, errors.New("unexpected end of JSON input")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case nil:
+		return NULL, nil
+	case bool:
+		return nativeBoolToBooleanObject(tok), nil
+	case string:
+		return &object.String{Value: tok}, nil
+	case json.Number:
+		value, err := strconv.ParseInt(tok.String(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("number %s is not a 64-bit integer", tok)
+		}
+		return &object.Integer{Value: value}, nil
+	case json.Delim:
+		if tok == '[' {
+			elements := []object.Object{}
+			for dec.More() {
+				el, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, el)
+			}
+			_, err := dec.Token() // ]
+			return &object.Array{Elements: elements}, err
+		}
+
+		hash := object.NewHash()
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			keyObj := &object.String{Value: key.(string)}
+			hash.Set(keyObj.HashKey(), object.HashPair{Key: keyObj, Value: value})
+		}
+		_, err := dec.Token() // }
+		return hash, err
+	}
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+// StringifyJSON 将对象序列化为 JSON 文本，indent 大于 0 时按该空格数缩进
+// 哈希表的键必须是字符串，结构体序列化为以字段名为键的 JSON 对象
+func StringifyJSON(obj object.Object, indent int) (string, error) {
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, obj, make(map[object.Object]bool)); err != nil {
+		return "", err
+	}
+	if indent <= 0 {
+		return buf.String(), nil
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", strings.Repeat(" ", indent)); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// encodeJSON 将对象写入 buf，seen 记录正在序列化的容器，用于发现循环引用
+func encodeJSON(buf *bytes.Buffer, obj object.Object, seen map[object.Object]bool) error {
+	switch obj := obj.(type) {
+	case *object.Null:
+		buf.WriteString("null")
+		return nil
+	case *object.Boolean:
+		buf.WriteString(strconv.FormatBool(obj.Value))
+		return nil
+	case *object.Integer:
+		buf.WriteString(strconv.FormatInt(obj.Value, 10))
+		return nil
+	case *object.String:
+		writeJSONString(buf, obj.Value)
+		return nil
+	case *object.Array, *object.Hash, *object.Struct:
+		if seen[obj] {
+			return errors.New("cannot convert cyclic value to json")
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+	default:
+		return fmt.Errorf("cannot convert %s to json", obj.Type())
+	}
+
+	switch obj := obj.(type) {
+	case *object.Array:
+		buf.WriteByte('[')
+		for i, el := range obj.Elements {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeJSON(buf, el, seen); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case *object.Hash:
+		buf.WriteByte('{')
+		for i, pair := range obj.Items() {
+			key, ok := pair.Key.(*object.String)
+			if !ok {
+				return fmt.Errorf("json object keys must be STRING, got %s", pair.Key.Type())
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, key.Value)
+			buf.WriteByte(':')
+			if err := encodeJSON(buf, pair.Value, seen); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case *object.Struct:
+		buf.WriteByte('{')
+		for i, field := range obj.StructType.Fields {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, field)
+			buf.WriteByte(':')
+			if err := encodeJSON(buf, obj.Fields[i], seen); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	}
+	return nil
+}
+
+// writeJSONString 写入带引号并转义的字符串，不转义 HTML 字符
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Encode 会追加换行符
+}
@@ -0,0 +1,86 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"context"
+	"fmt"
+)
+
+/**
+ * @Description: 执行限制：求值步数、调用深度、集合大小与取消/截止时间
+ */
+
+// 每求值多少个节点检查一次取消状态，循环回边与函数调用处总会检查
+const contextCheckInterval = 256
+
+// step 记录一次节点求值，超出步数限制、超时或被取消时返回错误
+func step(rt *object.Runtime) object.Object {
+	rt.Steps++
+	if rt.MaxSteps > 0 && rt.Steps > rt.MaxSteps {
+		return newKindError(object.STEP_LIMIT_ERROR, "step limit exceeded: %d", rt.MaxSteps)
+	}
+	if rt.Steps%contextCheckInterval == 0 {
+		return checkContext(rt)
+	}
+	return nil
+}
+
+// checkContext 检查执行是否已被取消或超过截止时间
+func checkContext(rt *object.Runtime) object.Object {
+	if rt.Context == nil {
+		return nil
+	}
+	switch rt.Context.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return newKindError(object.TIMEOUT_ERROR, "execution timed out")
+	default:
+		return newKindError(object.CANCELLED_ERROR, "execution cancelled")
+	}
+}
+
+// enterCall 进入函数调用，超出调用深度限制时返回错误
+func enterCall(rt *object.Runtime) object.Object {
+	if err := checkContext(rt); err != nil {
+		return err
+	}
+	if rt.MaxDepth > 0 && rt.Depth >= rt.MaxDepth {
+		return newKindError(object.DEPTH_LIMIT_ERROR, "maximum call depth exceeded: %d", rt.MaxDepth)
+	}
+	rt.Depth++
+	return nil
+}
+
+// exitCall 退出函数调用
+func exitCall(rt *object.Runtime) {
+	rt.Depth--
+}
+
+// checkAlloc 检查集合大小（数组、哈希元素个数或字符串长度）是否超出限制
+func checkAlloc(rt *object.Runtime, size int) object.Object {
+	if rt.MaxAlloc > 0 && size > rt.MaxAlloc {
+		return newKindError(object.ALLOC_LIMIT_ERROR, "allocation limit exceeded: %d > %d", size, rt.MaxAlloc)
+	}
+	return nil
+}
+
+// newKindError 创建指定类别的错误
+func newKindError(kind object.ErrorKind, format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: kind}
+}
+
+// isLimitError 判断是否为执行限制类错误或 exit，此类错误不能被脚本捕获
+func isLimitError(err *object.Error) bool {
+	return isLimitKind(err.Kind)
+}
+
+// isLimitKind 判断错误类别是否保留给执行限制与 exit，脚本不能以这些类别抛出错误
+func isLimitKind(kind object.ErrorKind) bool {
+	switch kind {
+	case object.STEP_LIMIT_ERROR, object.DEPTH_LIMIT_ERROR, object.ALLOC_LIMIT_ERROR,
+		object.TIMEOUT_ERROR, object.CANCELLED_ERROR, object.EXIT:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"math"
+	"math/big"
+)
+
+/**
+ * @Description: 数学内置函数，只支持整数；开启溢出检查时结果溢出返回错误
+ */
+
+var mathBuiltins = map[string]*object.Builtin{
+	"abs": { //绝对值
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("abs", args, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			x := intArg(args[0])
+			if x >= 0 {
+				return args[0]
+			}
+			if rt.CheckedArithmetic && x == math.MinInt64 {
+				return newKindError(object.OVERFLOW_ERROR, "integer overflow: abs(%d)", x)
+			}
+			return &object.Integer{Value: -x}
+		},
+	},
+	"min": { //最小值，参数为多个整数或一个整数数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return extremum("min", args, func(a, b int64) bool { return a < b })
+		},
+	},
+	"max": { //最大值，参数为多个整数或一个整数数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return extremum("max", args, func(a, b int64) bool { return a > b })
+		},
+	},
+	"pow": { //整数幂，指数不能为负数
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("pow", args, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			base, exp := intArg(args[0]), intArg(args[1])
+			if exp < 0 {
+				return newError("negative exponent: %d", exp)
+			}
+			if rt.CheckedArithmetic && powOverflows(base, exp) {
+				return newKindError(object.OVERFLOW_ERROR, "integer overflow: pow(%d, %d)", base, exp)
+			}
+			return &object.Integer{Value: integerPow(base, exp)}
+		},
+	},
+	"sqrt": { //整数平方根，向下取整
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("sqrt", args, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			x := intArg(args[0])
+			if x < 0 {
+				return newError("square root of negative number: %d", x)
+			}
+			return &object.Integer{Value: integerSqrt(x)}
+		},
+	},
+	"floor": { //floor(x) 返回 x，floor(a, b) 为向下取整的 a / b
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return roundedDivision("floor", args, rt, func(q, r, b int64) int64 {
+				if r != 0 && (r < 0) != (b < 0) {
+					return q - 1
+				}
+				return q
+			})
+		},
+	},
+	"ceil": { //ceil(x) 返回 x，ceil(a, b) 为向上取整的 a / b
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return roundedDivision("ceil", args, rt, func(q, r, b int64) int64 {
+				if r != 0 && (r < 0) == (b < 0) {
+					return q + 1
+				}
+				return q
+			})
+		},
+	},
+	"round": { //round(x) 返回 x，round(a, b) 为四舍五入的 a / b，恰好一半时远离零
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return roundedDivision("round", args, rt, func(q, r, b int64) int64 {
+				if absUint(r) < absUint(b)-absUint(r) {
+					return q
+				}
+				if (r < 0) != (b < 0) {
+					return q - 1
+				}
+				return q + 1
+			})
+		},
+	},
+	"clamp": { //将 x 限制在 [lo, hi] 范围内
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("clamp", args, object.INTEGER_OBJ, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			x, lo, hi := intArg(args[0]), intArg(args[1]), intArg(args[2])
+			if lo > hi {
+				return newError("`clamp` lower bound %d is greater than upper bound %d", lo, hi)
+			}
+			return &object.Integer{Value: clamp(x, lo, hi)}
+		},
+	},
+	"rand": { //rand() 返回非负随机整数，rand(n) 返回 [0, n) 中的随机整数
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return &object.Integer{Value: rt.Rand.Int63()}
+			}
+			if err := checkArgs("rand", args, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			n := intArg(args[0])
+			if n <= 0 {
+				return newError("argument to `rand` must be positive, got %d", n)
+			}
+			return &object.Integer{Value: rt.Rand.Int63n(n)}
+		},
+	},
+	"rand_int": { //返回 [lo, hi] 中的随机整数，包含两端
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("rand_int", args, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			lo, hi := intArg(args[0]), intArg(args[1])
+			if lo > hi {
+				return newError("`rand_int` lower bound %d is greater than upper bound %d", lo, hi)
+			}
+			return &object.Integer{Value: randomBetween(rt, lo, hi)}
+		},
+	},
+	"rand_seed": { //设置随机数种子，相同的种子产生相同的随机数序列
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("rand_seed", args, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			rt.Rand.Seed(intArg(args[0]))
+			return NULL
+		},
+	},
+}
+
+// intArg 取出已校验为整数的参数
+func intArg(arg object.Object) int64 {
+	return arg.(*object.Integer).Value
+}
+
+// extremum 返回 better 意义下最优的整数，参数为多个整数或一个整数数组
+func extremum(name string, args []object.Object, better func(a, b int64) bool) object.Object {
+	if len(args) == 0 {
+		return newError("wrong number of arguments. got=0, want>=1")
+	}
+	values, fromArray := args, false
+	if arr, ok := args[0].(*object.Array); ok && len(args) == 1 {
+		if len(arr.Elements) == 0 {
+			return newError("`%s` of empty array", name)
+		}
+		values, fromArray = arr.Elements, true
+	}
+
+	best := values[0]
+	for i, value := range values {
+		switch {
+		case value.Type() == object.INTEGER_OBJ:
+		case fromArray:
+			return newError("element %d of `%s` must be INTEGER, got %s", i, name, value.Type())
+		case len(values) == 1:
+			return newError("argument to `%s` must be INTEGER or ARRAY, got %s", name, value.Type())
+		default:
+			return newError("argument %d to `%s` must be INTEGER, got %s", i+1, name, value.Type())
+		}
+		if better(intArg(value), intArg(best)) {
+			best = value
+		}
+	}
+	return best
+}
+
+// roundedDivision 带取整方式的整数除法，adjust 根据商、余数和除数修正截断的商
+func roundedDivision(name string, args []object.Object, rt *object.Runtime, adjust func(q, r, b int64) int64) object.Object {
+	switch len(args) {
+	case 1:
+		if err := checkArgs(name, args, object.INTEGER_OBJ); err != nil {
+			return err
+		}
+		return args[0]
+	case 2:
+		if err := checkArgs(name, args, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+			return err
+		}
+	default:
+		return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+
+	a, b := intArg(args[0]), intArg(args[1])
+	if b == 0 {
+		return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %s(%d, 0)", name, a)
+	}
+	if rt.CheckedArithmetic && a == math.MinInt64 && b == -1 {
+		return newKindError(object.OVERFLOW_ERROR, "integer overflow: %s(%d, %d)", name, a, b)
+	}
+	return &object.Integer{Value: adjust(a/b, a%b, b)}
+}
+
+// absUint 返回整数的绝对值，math.MinInt64 也不会溢出
+func absUint(x int64) uint64 {
+	if x < 0 {
+		return uint64(-x)
+	}
+	return uint64(x)
+}
+
+// integerPow 快速幂，溢出时按二进制补码回绕
+func integerPow(base, exp int64) int64 {
+	result := int64(1)
+	for exp > 0 {
+		if exp&1 == 1 {
+			result *= base
+		}
+		exp >>= 1
+		base *= base
+	}
+	return result
+}
+
+// powOverflows 判断 base 的 exp 次幂是否超出 int64 范围
+func powOverflows(base, exp int64) bool {
+	if base >= -1 && base <= 1 {
+		return false
+	}
+	// |base| >= 2 时 63 次幂以上必然溢出，其余情况用大整数精确计算
+	if exp >= 64 {
+		return true
+	}
+	return !new(big.Int).Exp(big.NewInt(base), big.NewInt(exp), nil).IsInt64()
+}
+
+// integerSqrt 非负整数的平方根，向下取整
+func integerSqrt(x int64) int64 {
+	r := uint64(math.Sqrt(float64(x)))
+	// 浮点数精度不足时修正结果
+	for r*r > uint64(x) {
+		r--
+	}
+	for (r+1)*(r+1) <= uint64(x) {
+		r++
+	}
+	return int64(r)
+}
+
+// randomBetween 返回 [lo, hi] 中均匀分布的随机整数
+func randomBetween(rt *object.Runtime, lo, hi int64) int64 {
+	span := uint64(hi) - uint64(lo) + 1
+	switch {
+	case span == 0: // [MinInt64, MaxInt64]
+		return int64(rt.Rand.Uint64())
+	case span <= math.MaxInt64:
+		return lo + rt.Rand.Int63n(int64(span))
+	default:
+		for {
+			if v := rt.Rand.Uint64(); v < span {
+				return int64(uint64(lo) + v)
+			}
+		}
+	}
+}
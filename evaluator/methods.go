@@ -0,0 +1,63 @@
+package evaluator
+
+import "Cmicro-Compiler/object"
+
+/**
+ * @Description: 按类型分派的内置方法，value.method(args) 等价于以 value 作为第一个参数调用对应函数
+ */
+
+// 各类型可以作为方法调用的内置函数
+var methods = map[object.ObjectType][]string{
+	object.ARRAY_OBJ: {"len", "first", "last", "rest", "push", "join", "map", "filter", "reduce", "sort",
+		"reverse", "zip", "any", "all", "index_of", "contains"},
+	object.STRING_OBJ: {"len", "split", "trim", "upper", "lower", "replace", "contains", "index_of",
+		"starts_with", "ends_with", "substr", "repeat", "format", "re_match", "re_find_all", "re_replace", "re_split"},
+	object.HASH_OBJ: {"len", "keys", "values", "items", "has", "delete", "merge"},
+}
+
+// lookupMethod 查找类型的内置方法
+func lookupMethod(t object.ObjectType, name string) (*object.Builtin, bool) {
+	for _, method := range methods[t] {
+		if method == name {
+			return builtins[name], true
+		}
+	}
+	return nil, false
+}
+
+// evalMemberExpression 成员访问求值，obj.name
+// 哈希表优先按字符串键查找，其余类型查找内置方法
+func evalMemberExpression(obj object.Object, name string) object.Object {
+	switch obj := obj.(type) {
+	case *object.Module:
+		return evalModuleMember(obj, name)
+	case *object.Exception:
+		return evalExceptionField(obj, name)
+	case *object.Struct:
+		return evalStructField(obj, name)
+	case *object.Hash:
+		key := &object.String{Value: name}
+		if pair, ok := obj.Pairs[key.HashKey()]; ok {
+			return pair.Value
+		}
+		if _, ok := lookupMethod(object.HASH_OBJ, name); ok {
+			return evalMethod(obj, name)
+		}
+		return NULL
+	default:
+		return evalMethod(obj, name)
+	}
+}
+
+// evalMethod 查找 receiver 类型的内置方法，返回绑定了 receiver 的内置函数
+func evalMethod(receiver object.Object, name string) object.Object {
+	method, ok := lookupMethod(receiver.Type(), name)
+	if !ok {
+		return newKindError(object.TYPE_ERROR, "%s has no method %s", receiver.Type(), name)
+	}
+
+	fn := func(rt *object.Runtime, args ...object.Object) object.Object {
+		return method.Fn(rt, append([]object.Object{receiver}, args...)...)
+	}
+	return &object.Builtin{Fn: fn, Name: name, Doc: method.Doc}
+}
@@ -0,0 +1,148 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/ast"
+	"Cmicro-Compiler/lexer"
+	"Cmicro-Compiler/object"
+	"Cmicro-Compiler/parser"
+	"Cmicro-Compiler/types"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/**
+ * @Description: 模块导入：宿主注册的原生模块与脚本文件模块
+ */
+
+// evalImportStatement 导入模块并绑定到别名（默认为模块名或文件名）
+// 优先查找宿主注册的原生模块，否则按相对于当前文件的路径加载脚本文件
+func evalImportStatement(is *ast.ImportStatement, env *object.Environment) object.Object {
+	rt := env.Runtime()
+
+	module, ok := rt.Modules[is.Path]
+	if !ok {
+		loaded := loadFileModule(is.Path, rt)
+		if isError(loaded) {
+			return loaded
+		}
+		module = loaded.(*object.Module)
+	}
+
+	env.Set(is.Name(), module)
+	return nil
+}
+
+// loadFileModule 加载脚本文件模块，同一文件只会求值一次；运行时禁用文件系统时返回 PermissionError
+func loadFileModule(path string, rt *object.Runtime) object.Object {
+	if !rt.FileSystem {
+		return newKindError(object.PERMISSION_ERROR, "file system access is disabled: import %s", path)
+	}
+	absPath, err := resolveModulePath(path, rt)
+	if err != nil {
+		return newKindError(object.IMPORT_ERROR, "cannot resolve module %s: %s", path, err)
+	}
+	if module, ok := rt.ModuleCache[absPath]; ok {
+		return module
+	}
+
+	for idx, loading := range rt.ImportStack {
+		if loading == absPath {
+			return newKindError(object.IMPORT_ERROR, "import cycle: %s", importCycle(rt.ImportStack[idx:], absPath))
+		}
+	}
+
+	src, err := os.ReadFile(absPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return newKindError(object.IMPORT_ERROR, "module not found: %s", path)
+		}
+		return newKindError(object.IMPORT_ERROR, "cannot read module %s: %s", path, err)
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return newKindError(object.IMPORT_ERROR, "module %s has parser errors: %s", path, strings.Join(p.Errors(), "; "))
+	}
+	if !rt.SkipTypeCheck {
+		if diagnostics := types.Check(program); len(diagnostics) != 0 {
+			return newKindError(object.IMPORT_ERROR, "module %s has type errors: %s", path, diagnostics[0])
+		}
+	}
+
+	//模块在独立的全局环境中求值，只有 export 声明的名称对导入方可见；脚本参数 args 在每个模块中同样可用
+	moduleEnv := object.NewEnvironmentWithRuntime(rt)
+	if rt.Args != nil {
+		moduleEnv.Set("args", rt.Args)
+	}
+	if result := evalModule(program, absPath, moduleEnv); isError(result) {
+		return result
+	}
+
+	module := &object.Module{Name: moduleName(absPath), Members: make(map[string]object.Object), Env: moduleEnv}
+	for _, stmt := range program.Statements {
+		export, ok := stmt.(*ast.ExportStatement)
+		if !ok {
+			continue
+		}
+		name := export.Declaration.Name.Value
+		if value, ok := moduleEnv.Get(name); ok {
+			module.Members[name] = value
+		}
+	}
+	rt.ModuleCache[absPath] = module
+	return module
+}
+
+// evalModule 以 path 作为当前文件求值模块，求值期间 path 位于导入栈顶
+func evalModule(program *ast.Program, path string, env *object.Environment) object.Object {
+	rt := env.Runtime()
+	rt.ImportStack = append(rt.ImportStack, path)
+	defer func() { rt.ImportStack = rt.ImportStack[:len(rt.ImportStack)-1] }()
+	return Eval(program, env)
+}
+
+// resolveModulePath 将导入路径解析为绝对路径，相对路径以当前文件所在目录为基准，
+// 不在文件中执行时以工作目录为基准
+func resolveModulePath(path string, rt *object.Runtime) (string, error) {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+	if n := len(rt.ImportStack); n > 0 {
+		return filepath.Join(filepath.Dir(rt.ImportStack[n-1]), path), nil
+	}
+	return filepath.Abs(path)
+}
+
+// importCycle 生成循环导入链的描述，例如 a.cm -> b.cm -> a.cm
+func importCycle(stack []string, path string) string {
+	names := make([]string, 0, len(stack)+1)
+	for _, p := range stack {
+		names = append(names, filepath.Base(p))
+	}
+	names = append(names, filepath.Base(path))
+	return strings.Join(names, " -> ")
+}
+
+// moduleName 返回文件模块的名称，即去掉扩展名的文件名
+func moduleName(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// evalModuleMember 获取模块成员，文件模块的成员在访问时读取，导入方可以看到模块之后对导出变量的赋值
+func evalModuleMember(module *object.Module, name string) object.Object {
+	member, ok := module.Members[name]
+	if !ok {
+		return newKindError(object.NAME_ERROR, "module %s has no member %s", module.Name, name)
+	}
+	if module.Env != nil {
+		if value, ok := module.Env.Get(name); ok {
+			return value
+		}
+	}
+	return member
+}
@@ -0,0 +1,112 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"regexp"
+	"strings"
+)
+
+/**
+ * @Description: 正则表达式内置函数，与其他字符串函数一样第一个参数为字符串、第二个参数为表达式，
+ * 使用 Go regexp 的 RE2 语法，编译结果缓存在运行时中
+ */
+
+// 运行时最多缓存的正则表达式个数，超出时清空缓存
+const maxRegexpCache = 256
+
+var regexpBuiltins = map[string]*object.Builtin{
+	"re_match": { //判断字符串中是否存在匹配
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("re_match", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			re, err := compileRegexp(rt, stringArg(args[1]))
+			if err != nil {
+				return err
+			}
+			return nativeBoolToBooleanObject(re.MatchString(stringArg(args[0])))
+		},
+	},
+	"re_find_all": { //返回所有匹配，表达式含有分组时每个匹配为 [整体, 分组1, ...]
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("re_find_all", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			re, err := compileRegexp(rt, stringArg(args[1]))
+			if err != nil {
+				return err
+			}
+
+			matches := re.FindAllStringSubmatch(stringArg(args[0]), -1)
+			if err := checkAlloc(rt, len(matches)); err != nil {
+				return err
+			}
+			elements := make([]object.Object, len(matches))
+			for i, match := range matches {
+				if re.NumSubexp() == 0 {
+					elements[i] = &object.String{Value: match[0]}
+					continue
+				}
+				elements[i] = stringArray(match)
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"re_replace": { //替换所有匹配，替换文本中的 $1、${name} 引用分组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("re_replace", args, object.STRING_OBJ, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			re, err := compileRegexp(rt, stringArg(args[1]))
+			if err != nil {
+				return err
+			}
+			result := re.ReplaceAllString(stringArg(args[0]), stringArg(args[2]))
+			if err := checkAlloc(rt, len(result)); err != nil {
+				return err
+			}
+			return &object.String{Value: result}
+		},
+	},
+	"re_split": { //按匹配拆分字符串
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("re_split", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			re, err := compileRegexp(rt, stringArg(args[1]))
+			if err != nil {
+				return err
+			}
+			parts := re.Split(stringArg(args[0]), -1)
+			if err := checkAlloc(rt, len(parts)); err != nil {
+				return err
+			}
+			return stringArray(parts)
+		},
+	},
+}
+
+// compileRegexp 编译正则表达式，优先使用运行时中的缓存
+func compileRegexp(rt *object.Runtime, pattern string) (*regexp.Regexp, object.Object) {
+	if re, ok := rt.RegexpCache[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, newError("invalid regular expression: %s", strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+	}
+	if len(rt.RegexpCache) >= maxRegexpCache {
+		rt.RegexpCache = make(map[string]*regexp.Regexp)
+	}
+	rt.RegexpCache[pattern] = re
+	return re, nil
+}
+
+// stringArray 将字符串切片转换为数组
+func stringArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+	for i, v := range values {
+		elements[i] = &object.String{Value: v}
+	}
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,117 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/ast"
+	"Cmicro-Compiler/object"
+)
+
+/**
+ * @Description: 负数索引、越界检查与切片
+ */
+
+// normalizeIndex 将索引转换为 [0, length) 内的下标，开启 NegativeIndex 时负数从末尾计数
+func normalizeIndex(idx int64, length int, rt *object.Runtime) (int64, bool) {
+	if idx < 0 && rt.NegativeIndex {
+		idx += int64(length)
+	}
+	return idx, idx >= 0 && idx < int64(length)
+}
+
+// indexOutOfRange 索引越界时的结果，严格模式下为错误，否则为 null
+func indexOutOfRange(idx int64, length int, rt *object.Runtime) object.Object {
+	if rt.StrictIndex {
+		return newKindError(object.INDEX_ERROR, "index out of range: %d (length %d)", idx, length)
+	}
+	return NULL
+}
+
+// evalSliceExpression 切片求值，支持数组与字符串，字符串按 UTF-8 字符切片
+func evalSliceExpression(se *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(se.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	var length int
+	var runes []rune
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		runes = []rune(left.Value)
+		length = len(runes)
+	default:
+		return newKindError(object.TYPE_ERROR, "slice operator not supported: %s", left.Type())
+	}
+
+	start, err := evalSliceBound(se.Start, 0, env)
+	if err != nil {
+		return err
+	}
+	end, err := evalSliceBound(se.End, int64(length), env)
+	if err != nil {
+		return err
+	}
+	lo, hi, err := sliceBounds(start, end, length, env.Runtime())
+	if err != nil {
+		return err
+	}
+
+	if left.Type() == object.STRING_OBJ {
+		return &object.String{Value: string(runes[lo:hi])}
+	}
+	elements := make([]object.Object, hi-lo)
+	copy(elements, left.(*object.Array).Elements[lo:hi])
+	return &object.Array{Elements: elements}
+}
+
+// evalSliceBound 求值切片边界，省略时取默认值
+func evalSliceBound(node ast.Expression, def int64, env *object.Environment) (int64, *object.Error) {
+	if node == nil {
+		return def, nil
+	}
+	bound := Eval(node, env)
+	if err, ok := bound.(*object.Error); ok {
+		return 0, err
+	}
+	integer, ok := bound.(*object.Integer)
+	if !ok {
+		return 0, newKindError(object.TYPE_ERROR, "slice index must be INTEGER, got %s", bound.Type())
+	}
+	return integer.Value, nil
+}
+
+// sliceBounds 计算切片的下标范围；非严格模式下越界的边界会被截断到 [0, length]
+func sliceBounds(start, end int64, length int, rt *object.Runtime) (int, int, *object.Error) {
+	lo, hi := start, end
+	if rt.NegativeIndex {
+		if lo < 0 {
+			lo += int64(length)
+		}
+		if hi < 0 {
+			hi += int64(length)
+		}
+	}
+
+	if rt.StrictIndex {
+		if lo < 0 || hi > int64(length) || lo > hi {
+			return 0, 0, newKindError(object.INDEX_ERROR, "slice bounds out of range: [%d:%d] (length %d)", start, end, length)
+		}
+		return int(lo), int(hi), nil
+	}
+
+	lo = clamp(lo, 0, int64(length))
+	hi = clamp(hi, lo, int64(length))
+	return int(lo), int(hi), nil
+}
+
+// clamp 将 n 限制在 [lo, hi] 内
+func clamp(n, lo, hi int64) int64 {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
@@ -0,0 +1,285 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/**
+ * @Description: 字符串内置函数，字符串的下标与长度均按 UTF-8 字符计算，与 len、索引和切片保持一致
+ */
+
+var stringBuiltins = map[string]*object.Builtin{
+	"split": { //按分隔符拆分字符串，分隔符为空时按 UTF-8 字符拆分
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("split", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			parts := strings.Split(stringArg(args[0]), stringArg(args[1]))
+			if err := checkAlloc(rt, len(parts)); err != nil {
+				return err
+			}
+			elements := make([]object.Object, len(parts))
+			for i, part := range parts {
+				elements[i] = &object.String{Value: part}
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"join": { //用分隔符连接字符串数组
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("join", args, object.ARRAY_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			elements := args[0].(*object.Array).Elements
+			parts := make([]string, len(elements))
+			for i, el := range elements {
+				str, ok := el.(*object.String)
+				if !ok {
+					return newError("elements of argument 1 to `join` must be STRING, got %s at index %d", el.Type(), i)
+				}
+				parts[i] = str.Value
+			}
+			result := strings.Join(parts, stringArg(args[1]))
+			if err := checkAlloc(rt, len(result)); err != nil {
+				return err
+			}
+			return &object.String{Value: result}
+		},
+	},
+	"trim": { //去除首尾空白，可选参数为要去除的字符集合
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) == 2 {
+				if err := checkArgs("trim", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+					return err
+				}
+				return &object.String{Value: strings.Trim(stringArg(args[0]), stringArg(args[1]))}
+			}
+			if err := checkArgs("trim", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return &object.String{Value: strings.TrimSpace(stringArg(args[0]))}
+		},
+	},
+	"upper": { //转换为大写
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("upper", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return &object.String{Value: strings.ToUpper(stringArg(args[0]))}
+		},
+	},
+	"lower": { //转换为小写
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("lower", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return &object.String{Value: strings.ToLower(stringArg(args[0]))}
+		},
+	},
+	"replace": { //替换所有出现的子串
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("replace", args, object.STRING_OBJ, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			result := strings.ReplaceAll(stringArg(args[0]), stringArg(args[1]), stringArg(args[2]))
+			if err := checkAlloc(rt, len(result)); err != nil {
+				return err
+			}
+			return &object.String{Value: result}
+		},
+	},
+	"contains": { //判断字符串是否包含子串，或数组是否包含与 x 相等的元素
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			idx := indexOf("contains", args)
+			if isError(idx) {
+				return idx
+			}
+			return nativeBoolToBooleanObject(idx.(*object.Integer).Value >= 0)
+		},
+	},
+	"index_of": { //子串或元素第一次出现的位置，不存在时为 -1
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return indexOf("index_of", args)
+		},
+	},
+	"starts_with": { //判断是否以指定前缀开头
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("starts_with", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return nativeBoolToBooleanObject(strings.HasPrefix(stringArg(args[0]), stringArg(args[1])))
+		},
+	},
+	"ends_with": { //判断是否以指定后缀结尾
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("ends_with", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return nativeBoolToBooleanObject(strings.HasSuffix(stringArg(args[0]), stringArg(args[1])))
+		},
+	},
+	"substr": { //从 start 开始截取 length 个字符，省略 length 时截取到末尾
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) == 2 {
+				if err := checkArgs("substr", args, object.STRING_OBJ, object.INTEGER_OBJ); err != nil {
+					return err
+				}
+				args = append(args, &object.Integer{Value: int64(utf8.RuneCountInString(stringArg(args[0])))})
+			}
+			if err := checkArgs("substr", args, object.STRING_OBJ, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+
+			runes := []rune(stringArg(args[0]))
+			start := args[1].(*object.Integer).Value
+			length := args[2].(*object.Integer).Value
+			if start < 0 || start > int64(len(runes)) {
+				return newError("start index out of range: %d (length %d)", start, len(runes))
+			}
+			if length < 0 {
+				return newError("negative substring length: %d", length)
+			}
+			// 先将 length 截断到剩余长度，避免 start+length 溢出
+			length = clamp(length, 0, int64(len(runes))-start)
+			return &object.String{Value: string(runes[start : start+length])}
+		},
+	},
+	"repeat": { //将字符串重复 n 次
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("repeat", args, object.STRING_OBJ, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			return evalStringRepeat(args[0].(*object.String), args[1].(*object.Integer), rt)
+		},
+	},
+	"format": { //格式化字符串，支持 %s %v %d %q %%
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return formatBuiltin("format", rt, args)
+		},
+	},
+	"sprintf": { //format 的别名
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return formatBuiltin("sprintf", rt, args)
+		},
+	},
+}
+
+// indexOf 在字符串中查找子串，或在数组中查找相等的元素
+func indexOf(name string, args []object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	switch haystack := args[0].(type) {
+	case *object.String:
+		if err := checkArgs(name, args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+			return err
+		}
+		idx := strings.Index(haystack.Value, stringArg(args[1]))
+		if idx < 0 {
+			return &object.Integer{Value: -1}
+		}
+		return &object.Integer{Value: int64(utf8.RuneCountInString(haystack.Value[:idx]))}
+	case *object.Array:
+		for i, el := range haystack.Elements {
+			if objectsEqual(el, args[1]) {
+				return &object.Integer{Value: int64(i)}
+			}
+		}
+		return &object.Integer{Value: -1}
+	default:
+		return newError("argument 1 to `%s` must be STRING or ARRAY, got %s", name, args[0].Type())
+	}
+}
+
+// formatBuiltin 校验格式串参数并格式化
+func formatBuiltin(name string, rt *object.Runtime, args []object.Object) object.Object {
+	if len(args) == 0 {
+		return newError("wrong number of arguments. got=0, want>=1")
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newError("argument 1 to `%s` must be STRING, got %s", name, args[0].Type())
+	}
+
+	result, err := formatString(name, stringArg(args[0]), args[1:])
+	if err != nil {
+		return err
+	}
+	if err := checkAlloc(rt, len(result)); err != nil {
+		return err
+	}
+	return &object.String{Value: result}
+}
+
+// formatString 按格式串依次替换参数：%s、%v 输出值的文本形式，%d 要求整数，%q 输出带引号的字符串
+func formatString(name, format string, args []object.Object) (string, object.Object) {
+	var out strings.Builder
+	next := 0
+
+	for i := 0; i < len(format); i++ {
+		ch := format[i]
+		if ch != '%' {
+			out.WriteByte(ch)
+			continue
+		}
+		if i+1 >= len(format) {
+			return "", newError("`%s`: format ends with a lone %%", name)
+		}
+		i++
+		verb := format[i]
+		if verb == '%' {
+			out.WriteByte('%')
+			continue
+		}
+
+		if next >= len(args) {
+			return "", newError("`%s`: missing argument for %%%c", name, verb)
+		}
+		arg := args[next]
+		next++
+
+		switch verb {
+		case 's', 'v':
+			out.WriteString(arg.Inspect())
+		case 'd':
+			integer, ok := arg.(*object.Integer)
+			if !ok {
+				return "", newError("`%s`: %%d requires INTEGER, got %s", name, arg.Type())
+			}
+			out.WriteString(strconv.FormatInt(integer.Value, 10))
+		case 'q':
+			out.WriteString(strconv.Quote(arg.Inspect()))
+		default:
+			return "", newError("`%s`: unknown verb %%%c", name, verb)
+		}
+	}
+
+	if next < len(args) {
+		return "", newError("`%s`: too many arguments. got=%d, want=%d", name, len(args), next)
+	}
+	return out.String(), nil
+}
+
+// checkArgs 校验参数个数与类型，错误信息与 first、push 等内置函数保持一致
+func checkArgs(name string, args []object.Object, types ...object.ObjectType) object.Object {
+	if len(args) != len(types) {
+		return newError("wrong number of arguments. got=%d, want=%d", len(args), len(types))
+	}
+	for i, t := range types {
+		if args[i].Type() == t {
+			continue
+		}
+		if len(types) == 1 {
+			return newError("argument to `%s` must be %s, got %s", name, t, args[i].Type())
+		}
+		return newError("argument %d to `%s` must be %s, got %s", i+1, name, t, args[i].Type())
+	}
+	return nil
+}
+
+// stringArg 取出已校验为字符串的参数
+func stringArg(arg object.Object) string {
+	return arg.(*object.String).Value
+}
@@ -0,0 +1,147 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/ast"
+	"Cmicro-Compiler/object"
+)
+
+/**
+ * @Description: 结构体声明、构造与字段读写
+ */
+
+// evalStructStatement 定义结构体类型，并以结构体名绑定到当前环境
+func evalStructStatement(ss *ast.StructStatement, env *object.Environment) object.Object {
+	st := &object.StructType{Name: ss.Name.Value}
+	for i, field := range ss.Fields {
+		if st.FieldIndex(field.Value) >= 0 {
+			return newKindError(object.TYPE_ERROR, "duplicate field %s in struct %s", field.Value, st.Name)
+		}
+		fieldType := ""
+		if ss.FieldTypes[i] != nil {
+			fieldType = ss.FieldTypes[i].Value
+		}
+		st.Fields = append(st.Fields, field.Value)
+		st.FieldTypes = append(st.FieldTypes, fieldType)
+	}
+
+	env.Set(st.Name, st)
+	return nil
+}
+
+// evalStructLiteral 构造结构体实例，未给出的字段取对应类型的零值
+func evalStructLiteral(sl *ast.StructLiteral, env *object.Environment) object.Object {
+	obj := Eval(sl.Name, env)
+	if isError(obj) {
+		return obj
+	}
+	st, ok := obj.(*object.StructType)
+	if !ok {
+		return newKindError(object.TYPE_ERROR, "%s is not a struct", sl.Name.Value)
+	}
+
+	s := &object.Struct{StructType: st, Fields: make([]object.Object, len(st.Fields))}
+	for i, fieldType := range st.FieldTypes {
+		s.Fields[i] = zeroValue(fieldType)
+	}
+
+	seen := make(map[string]bool)
+	for i, field := range sl.Fields {
+		if seen[field.Value] {
+			return newKindError(object.TYPE_ERROR, "duplicate field %s in %s literal", field.Value, st.Name)
+		}
+		seen[field.Value] = true
+
+		value := Eval(sl.Values[i], env)
+		if isError(value) {
+			return value
+		}
+		if err := setStructField(s, field.Value, value); err != nil {
+			return err
+		}
+	}
+	return s
+}
+
+// evalStructField 读取结构体字段
+func evalStructField(s *object.Struct, name string) object.Object {
+	idx := s.StructType.FieldIndex(name)
+	if idx < 0 {
+		return newKindError(object.TYPE_ERROR, "struct %s has no field %s", s.StructType.Name, name)
+	}
+	return s.Fields[idx]
+}
+
+// setStructField 设置结构体字段，字段带类型标注时检查值的类型
+func setStructField(s *object.Struct, name string, value object.Object) *object.Error {
+	st := s.StructType
+	idx := st.FieldIndex(name)
+	if idx < 0 {
+		return newKindError(object.TYPE_ERROR, "struct %s has no field %s", st.Name, name)
+	}
+	if !fieldTypeMatches(st.FieldTypes[idx], value) {
+		return newKindError(object.TYPE_ERROR, "cannot use %s value as %s in field %s of %s",
+			value.Type(), st.FieldTypes[idx], name, st.Name)
+	}
+	s.Fields[idx] = value
+	return nil
+}
+
+// evalMemberAssignStatement 成员赋值，支持结构体字段与哈希表的字符串键
+func evalMemberAssignStatement(ma *ast.MemberAssignStatement, env *object.Environment) object.Object {
+	target := Eval(ma.Target.Object, env)
+	if isError(target) {
+		return target
+	}
+	value := Eval(ma.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	name := ma.Target.Property.Value
+	switch target := target.(type) {
+	case *object.Struct:
+		if err := setStructField(target, name, value); err != nil {
+			return err
+		}
+	case *object.Hash:
+		key := &object.String{Value: name}
+		target.Set(key.HashKey(), object.HashPair{Key: key, Value: value})
+	default:
+		return newKindError(object.TYPE_ERROR, "cannot assign to member of %s", target.Type())
+	}
+	return value
+}
+
+// zeroValue 返回字段类型的零值，未标注类型或结构体类型的字段为 null
+func zeroValue(fieldType string) object.Object {
+	switch fieldType {
+	case "int":
+		return &object.Integer{Value: 0}
+	case "string":
+		return &object.String{Value: ""}
+	case "bool":
+		return FALSE
+	default:
+		return NULL
+	}
+}
+
+// fieldTypeMatches 判断值能否赋给对应类型的字段，结构体类型的字段可以为 null
+func fieldTypeMatches(fieldType string, value object.Object) bool {
+	switch fieldType {
+	case "":
+		return true
+	case "int":
+		return value.Type() == object.INTEGER_OBJ
+	case "string":
+		return value.Type() == object.STRING_OBJ
+	case "bool":
+		return value.Type() == object.BOOLEAN_OBJ
+	default:
+		if value == NULL {
+			return true
+		}
+		s, ok := value.(*object.Struct)
+		return ok && s.StructType.Name == fieldType
+	}
+}
@@ -0,0 +1,39 @@
+package evaluator
+
+import "Cmicro-Compiler/object"
+
+/**
+ * @Description: 与宿主进程交互的内置函数
+ */
+
+var systemBuiltins = map[string]*object.Builtin{
+	"env": { //读取环境变量，不存在时返回 null
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("env", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			value, ok := rt.Getenv(stringArg(args[0]))
+			if !ok {
+				return NULL
+			}
+			return &object.String{Value: value}
+		},
+	},
+	"exit": { //结束脚本执行，可选参数为 0 到 255 之间的退出码，默认为 0；exit 不能被 catch 捕获，也不会执行 finally 块
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			code := &object.Integer{Value: 0}
+			if len(args) != 0 {
+				if err := checkArgs("exit", args, object.INTEGER_OBJ); err != nil {
+					return err
+				}
+				code = args[0].(*object.Integer)
+			}
+			if code.Value < 0 || code.Value > 255 {
+				return newError("exit code must be between 0 and 255, got %d", code.Value)
+			}
+			err := newKindError(object.EXIT, "exit status %d", code.Value)
+			err.Value = code
+			return err
+		},
+	},
+}
@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"Cmicro-Compiler/object"
+	"context"
+	"math"
+	"time"
+)
+
+/**
+ * @Description: 时间内置函数，时间来源为运行时的 Clock，时间均以毫秒为单位
+ */
+
+var timeBuiltins = map[string]*object.Builtin{
+	"now": { //当前的 Unix 时间戳（毫秒）
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("wrong number of arguments. got=%d, want=0", len(args))
+			}
+			return &object.Integer{Value: rt.Clock.Now().UnixMilli()}
+		},
+	},
+	"clock": { //单调时间（毫秒），只用于计算两次调用之间的间隔
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("wrong number of arguments. got=%d, want=0", len(args))
+			}
+			return &object.Integer{Value: rt.Clock.Monotonic().Milliseconds()}
+		},
+	},
+	"sleep": { //暂停执行 ms 毫秒，执行被取消或超时时提前结束
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if err := checkArgs("sleep", args, object.INTEGER_OBJ); err != nil {
+				return err
+			}
+			ms := intArg(args[0])
+			if ms < 0 {
+				return newError("sleep duration must not be negative, got %d", ms)
+			}
+
+			d := time.Duration(math.MaxInt64)
+			if ms < int64(d/time.Millisecond) {
+				d = time.Duration(ms) * time.Millisecond
+			}
+			ctx := rt.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if err := rt.Clock.Sleep(ctx, d); err != nil {
+				if ctxErr := checkContext(rt); ctxErr != nil {
+					return ctxErr
+				}
+				return newError("sleep failed: %s", err)
+			}
+			return NULL
+		},
+	},
+	"format_time": { //按 Go 的时间格式（如 "2006-01-02 15:04:05"）格式化毫秒时间戳，可选参数为时区名，默认为本地时区
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			var err object.Object
+			loc := time.Local
+			switch len(args) {
+			case 2:
+				err = checkArgs("format_time", args, object.INTEGER_OBJ, object.STRING_OBJ)
+			case 3:
+				err = checkArgs("format_time", args, object.INTEGER_OBJ, object.STRING_OBJ, object.STRING_OBJ)
+			default:
+				err = newError("wrong number of arguments. got=%d, want=2 or 3", len(args))
+			}
+			if err != nil {
+				return err
+			}
+			if len(args) == 3 {
+				var loadErr error
+				if loc, loadErr = time.LoadLocation(stringArg(args[2])); loadErr != nil {
+					return newError("unknown time zone: %s", stringArg(args[2]))
+				}
+			}
+			t := time.UnixMilli(intArg(args[0])).In(loc)
+			return &object.String{Value: t.Format(stringArg(args[1]))}
+		},
+	},
+}
@@ -0,0 +1,145 @@
+package interpreter
+
+import (
+	"Cmicro-Compiler/evaluator"
+	"Cmicro-Compiler/object"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+/**
+ * @Description: Go 值与对象之间的转换
+ */
+
+// ToObject 将 Go 值转换为对象
+// 支持 nil、布尔、整数、字符串、切片/数组以及键为字符串、整数或布尔的 map，object.Object 原样返回
+func ToObject(v interface{}) (object.Object, error) {
+	switch v := v.(type) {
+	case nil:
+		return evaluator.NULL, nil
+	case object.Object:
+		return v, nil
+	case bool:
+		if v {
+			return evaluator.TRUE, nil
+		}
+		return evaluator.FALSE, nil
+	case string:
+		return &object.String{Value: v}, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &object.Integer{Value: rv.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("integer overflow: %d", rv.Uint())
+		}
+		return &object.Integer{Value: int64(rv.Uint())}, nil
+	case reflect.Bool:
+		return ToObject(rv.Bool())
+	case reflect.String:
+		return ToObject(rv.String())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return evaluator.NULL, nil
+		}
+		elements := make([]object.Object, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			el, err := ToObject(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			elements[i] = el
+		}
+		return &object.Array{Elements: elements}, nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return evaluator.NULL, nil
+		}
+		// Go map 没有顺序，按键的文本排序后插入，保证结果确定
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		hash := object.NewHash()
+		for _, k := range keys {
+			key, err := ToObject(k.Interface())
+			if err != nil {
+				return nil, err
+			}
+			hashKey, ok := key.(object.Hashable)
+			if !ok {
+				return nil, fmt.Errorf("unusable as hash key: %s", key.Type())
+			}
+			value, err := ToObject(rv.MapIndex(k).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", key.Inspect(), err)
+			}
+			hash.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
+		}
+		return hash, nil
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return evaluator.NULL, nil
+		}
+		return ToObject(rv.Elem().Interface())
+	}
+
+	return nil, fmt.Errorf("cannot convert %T to object", v)
+}
+
+// FromObject 将对象转换为 Go 值
+// INTEGER 转为 int64，ARRAY 转为 []interface{}，HASH 转为 map[string]interface{}（键必须是字符串）
+func FromObject(obj object.Object) (interface{}, error) {
+	switch obj := obj.(type) {
+	case nil, *object.Null:
+		return nil, nil
+	case *object.Integer:
+		return obj.Value, nil
+	case *object.Boolean:
+		return obj.Value, nil
+	case *object.String:
+		return obj.Value, nil
+	case *object.Array:
+		elements := make([]interface{}, len(obj.Elements))
+		for i, el := range obj.Elements {
+			v, err := FromObject(el)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			elements[i] = v
+		}
+		return elements, nil
+	case *object.Hash:
+		m := make(map[string]interface{}, len(obj.Pairs))
+		for _, pair := range obj.Pairs {
+			key, ok := pair.Key.(*object.String)
+			if !ok {
+				return nil, fmt.Errorf("hash key must be STRING, got %s", pair.Key.Type())
+			}
+			v, err := FromObject(pair.Value)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", key.Value, err)
+			}
+			m[key.Value] = v
+		}
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("cannot convert %s to Go value", obj.Type())
+}
+
+// FromJSON 将 JSON 文本解析为对象，与脚本中的 json_parse 相同
+func FromJSON(src string) (object.Object, error) {
+	return evaluator.ParseJSON(src)
+}
+
+// ToJSON 将对象序列化为 JSON 文本，与脚本中的 json_stringify 相同，indent 为缩进的空格数
+func ToJSON(obj object.Object, indent int) (string, error) {
+	return evaluator.StringifyJSON(obj, indent)
+}
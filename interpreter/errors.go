@@ -0,0 +1,52 @@
+package interpreter
+
+import (
+	"Cmicro-Compiler/object"
+	"Cmicro-Compiler/types"
+	"fmt"
+	"strings"
+)
+
+/**
+ * @Description: 解释器返回的错误
+ */
+
+// ParseError 语法错误
+type ParseError struct {
+	Errors []string
+}
+
+func (e *ParseError) Error() string {
+	return "parser errors: " + strings.Join(e.Errors, "; ")
+}
+
+// TypeError 类型检查错误
+type TypeError struct {
+	Diagnostics []types.Diagnostic
+}
+
+func (e *TypeError) Error() string {
+	msgs := []string{}
+	for _, d := range e.Diagnostics {
+		msgs = append(msgs, d.String())
+	}
+	return "type errors: " + strings.Join(msgs, "; ")
+}
+
+// RuntimeError 运行时错误
+type RuntimeError struct {
+	Err *object.Error
+}
+
+func (e *RuntimeError) Error() string {
+	return e.Err.Message
+}
+
+// ExitError 脚本调用 exit 结束执行
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
@@ -0,0 +1,229 @@
+package interpreter
+
+import (
+	"Cmicro-Compiler/evaluator"
+	"Cmicro-Compiler/lexer"
+	"Cmicro-Compiler/object"
+	"Cmicro-Compiler/parser"
+	"Cmicro-Compiler/types"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+/**
+ * @File: interpreter
+ * @Description: 嵌入接口，供宿主 Go 程序直接运行脚本
+ */
+
+// Options 解释器选项
+type Options struct {
+	CheckedArithmetic bool // 整数运算溢出时返回错误
+	SkipTypeCheck     bool // 跳过求值前的静态类型检查
+	NegativeIndex     bool // 负数索引从末尾开始计数
+	StrictIndex       bool // 索引或切片越界时返回错误而不是 null
+	DisableFileSystem bool // 禁止脚本读写文件，用于沙箱环境
+
+	Clock    object.Clock // now、clock、sleep 使用的时间来源，为 nil 时使用系统时间
+	RandSeed *int64       // rand、rand_int 的随机数种子，为 nil 时使用当前时间，种子相同的解释器产生相同的随机数序列
+
+	Args []string          // 脚本中全局变量 args 的内容，通常为命令行中脚本路径之后的参数
+	Env  map[string]string // env 可以读取的环境变量，为 nil 时读取进程的环境变量
+
+	Stdin  io.Reader // input 的输入流，为 nil 时使用 os.Stdin
+	Stdout io.Writer // print、println 的输出流，为 nil 时使用 os.Stdout
+	Stderr io.Writer // eprint、eprintln 的输出流，为 nil 时使用 os.Stderr
+
+	Limits Limits // 执行限制，每次 Run 或 Call 单独计算
+}
+
+// Limits 执行限制，超出时返回带有对应 object.ErrorKind 的运行时错误
+type Limits struct {
+	MaxSteps int64         // 最多求值的节点数，0 表示不限制
+	MaxDepth int           // 最大调用深度，0 表示使用 object.DefaultMaxDepth
+	MaxAlloc int           // 数组、哈希元素个数及字符串长度的上限，0 表示不限制
+	Timeout  time.Duration // 单次执行的时长上限，0 表示不限制
+}
+
+// Interpreter 解释器，多次 Run 之间共享同一个全局环境
+type Interpreter struct {
+	options Options
+	env     *object.Environment
+	checker *types.Checker
+}
+
+func New(opts Options) *Interpreter {
+	rt := object.NewRuntime()
+	rt.CheckedArithmetic = opts.CheckedArithmetic
+	rt.SkipTypeCheck = opts.SkipTypeCheck
+	rt.NegativeIndex = opts.NegativeIndex
+	rt.StrictIndex = opts.StrictIndex
+	rt.FileSystem = !opts.DisableFileSystem
+	rt.MaxSteps = opts.Limits.MaxSteps
+	rt.MaxAlloc = opts.Limits.MaxAlloc
+	if opts.Limits.MaxDepth > 0 {
+		rt.MaxDepth = opts.Limits.MaxDepth
+	}
+	if opts.Clock != nil {
+		rt.Clock = opts.Clock
+	}
+	if opts.RandSeed != nil {
+		rt.Rand.Seed(*opts.RandSeed)
+	}
+	if opts.Env != nil {
+		rt.Getenv = func(name string) (string, bool) {
+			value, ok := opts.Env[name]
+			return value, ok
+		}
+	}
+	if opts.Stdin != nil {
+		rt.SetInput(opts.Stdin)
+	}
+	if opts.Stdout != nil {
+		rt.Stdout = opts.Stdout
+	}
+	if opts.Stderr != nil {
+		rt.Stderr = opts.Stderr
+	}
+
+	args := make([]object.Object, len(opts.Args))
+	for idx, arg := range opts.Args {
+		args[idx] = &object.String{Value: arg}
+	}
+	rt.Args = &object.Array{Elements: args}
+	env := object.NewEnvironmentWithRuntime(rt)
+	env.Set("args", rt.Args)
+
+	return &Interpreter{
+		options: opts,
+		env:     env,
+		checker: types.New(),
+	}
+}
+
+// Run 解析、检查并执行一段源码，返回最后一条语句的值
+// 声明语句等没有值的语句返回 nil
+func (i *Interpreter) Run(src string) (object.Object, error) {
+	return i.RunContext(context.Background(), src)
+}
+
+// RunContext 与 Run 相同，ctx 被取消时执行会在下一次循环或函数调用处中止
+func (i *Interpreter) RunContext(ctx context.Context, src string) (object.Object, error) {
+	return i.run(ctx, src, "")
+}
+
+// RunFile 读取并执行脚本文件，文件中的相对导入以该文件所在目录为基准
+func (i *Interpreter) RunFile(path string) (object.Object, error) {
+	return i.RunFileContext(context.Background(), path)
+}
+
+// RunFileContext 与 RunFile 相同，ctx 被取消时执行会中止
+func (i *Interpreter) RunFileContext(ctx context.Context, path string) (object.Object, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	src, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+	return i.run(ctx, string(src), absPath)
+}
+
+// run 解析、检查并执行源码，path 不为空时作为当前文件的绝对路径
+func (i *Interpreter) run(ctx context.Context, src string, path string) (object.Object, error) {
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, &ParseError{Errors: p.Errors()}
+	}
+
+	if !i.options.SkipTypeCheck {
+		if diagnostics := i.checker.Check(program); len(diagnostics) != 0 {
+			return nil, &TypeError{Diagnostics: diagnostics}
+		}
+	}
+
+	rt := i.env.Runtime()
+	if path != "" {
+		rt.ImportStack = append(rt.ImportStack, path)
+		defer func() { rt.ImportStack = rt.ImportStack[:len(rt.ImportStack)-1] }()
+	}
+
+	defer i.begin(ctx)()
+	return result(evaluator.SafeEval(program, i.env))
+}
+
+// Call 调用全局环境中名为 fnName 的函数，参数会先转换为对象
+func (i *Interpreter) Call(fnName string, args ...interface{}) (object.Object, error) {
+	return i.CallContext(context.Background(), fnName, args...)
+}
+
+// CallContext 与 Call 相同，ctx 被取消时调用会中止
+func (i *Interpreter) CallContext(ctx context.Context, fnName string, args ...interface{}) (object.Object, error) {
+	fn, ok := i.env.Get(fnName)
+	if !ok {
+		return nil, fmt.Errorf("function not found: %s", fnName)
+	}
+	if fn.Type() != object.FUNCTION_OBJ && fn.Type() != object.BUILTIN_OBJ {
+		return nil, fmt.Errorf("not a function: %s is %s", fnName, fn.Type())
+	}
+
+	objs := make([]object.Object, len(args))
+	for idx, arg := range args {
+		obj, err := ToObject(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d to %s: %w", idx+1, fnName, err)
+		}
+		objs[idx] = obj
+	}
+
+	defer i.begin(ctx)()
+	return result(evaluator.ApplyFunction(fn, objs, i.env.Runtime()))
+}
+
+// begin 开始一次执行：重置步数并设置取消上下文与截止时间，返回结束时需调用的清理函数
+func (i *Interpreter) begin(ctx context.Context) func() {
+	rt := i.env.Runtime()
+	rt.Steps = 0
+	rt.Depth = 0
+
+	cancel := func() {}
+	if i.options.Limits.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, i.options.Limits.Timeout)
+	}
+	rt.Context = ctx
+	return func() {
+		cancel()
+		rt.Context = nil
+	}
+}
+
+// SetGlobal 设置全局变量，value 会先转换为对象
+func (i *Interpreter) SetGlobal(name string, value interface{}) error {
+	obj, err := ToObject(value)
+	if err != nil {
+		return fmt.Errorf("global %s: %w", name, err)
+	}
+	i.env.Set(name, obj)
+	return nil
+}
+
+// GetGlobal 获取全局变量
+func (i *Interpreter) GetGlobal(name string) (object.Object, bool) {
+	return i.env.Get(name)
+}
+
+// 将求值结果中的错误对象转换为 Go 错误，脚本调用 exit 时返回 ExitError
+func result(obj object.Object) (object.Object, error) {
+	if errObj, ok := obj.(*object.Error); ok {
+		if code, ok := errObj.Value.(*object.Integer); ok && errObj.Kind == object.EXIT {
+			return nil, &ExitError{Code: int(code.Value)}
+		}
+		return nil, &RuntimeError{Err: errObj}
+	}
+	return obj, nil
+}
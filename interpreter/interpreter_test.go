@@ -0,0 +1,503 @@
+package interpreter
+
+import (
+	"Cmicro-Compiler/object"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	interp := New(Options{})
+
+	if _, err := interp.Run("let add = fn(a, b) { a + b };"); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	result, err := interp.Run("add(1, 2)")
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	testIntegerObject(t, result, 3)
+}
+
+func TestRunErrors(t *testing.T) {
+	interp := New(Options{})
+
+	var parseErr *ParseError
+	if _, err := interp.Run("let = 1;"); !errors.As(err, &parseErr) {
+		t.Errorf("expected ParseError, got=%v", err)
+	}
+
+	var typeErr *TypeError
+	if _, err := interp.Run(`"a" - 1`); !errors.As(err, &typeErr) {
+		t.Errorf("expected TypeError, got=%v", err)
+	}
+
+	var runtimeErr *RuntimeError
+	if _, err := interp.Run("1 / 0"); !errors.As(err, &runtimeErr) {
+		t.Errorf("expected RuntimeError, got=%v", err)
+	} else if runtimeErr.Error() != "division by zero: 1 / 0" {
+		t.Errorf("wrong error message. got=%q", runtimeErr.Error())
+	}
+}
+
+func TestRedirectIO(t *testing.T) {
+	var out bytes.Buffer
+	interp := New(Options{Stdin: strings.NewReader("cmicro\n"), Stdout: &out})
+
+	if _, err := interp.Run(`let name = input("who? "); println("hi " + name);`); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if out.String() != "who? hi cmicro\n" {
+		t.Errorf("wrong output. got=%q", out.String())
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	interp := New(Options{Limits: Limits{Timeout: 20 * time.Millisecond}})
+
+	_, err := interp.Run("for(let i = 0; i < 1; i){}")
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("expected RuntimeError, got=%v", err)
+	}
+	if runtimeErr.Err.Kind != object.TIMEOUT_ERROR {
+		t.Errorf("wrong error kind. got=%q", runtimeErr.Err.Kind)
+	}
+
+	// 每次执行重新计时
+	result, err := interp.Run("1 + 1")
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	testIntegerObject(t, result, 2)
+}
+
+func TestRunContextCancel(t *testing.T) {
+	interp := New(Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := interp.RunContext(ctx, "let spin = fn(){ for(let i = 0; i < 1; i){} }; spin();")
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("expected RuntimeError, got=%v", err)
+	}
+	if runtimeErr.Err.Kind != object.CANCELLED_ERROR {
+		t.Errorf("wrong error kind. got=%q", runtimeErr.Err.Kind)
+	}
+
+	// 已取消的上下文在函数调用处即被检查
+	if _, err := interp.CallContext(ctx, "spin"); err == nil || err.Error() != "execution cancelled" {
+		t.Errorf("expected cancelled error, got=%v", err)
+	}
+}
+
+func TestRandSeed(t *testing.T) {
+	seed := int64(7)
+	run := func() string {
+		result, err := New(Options{RandSeed: &seed}).Run("[rand(), rand(100), rand_int(-5, 5)]")
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		return result.Inspect()
+	}
+	if first, second := run(), run(); first != second {
+		t.Errorf("same seed produced different sequences: %s, %s", first, second)
+	}
+}
+
+func TestArgsEnvExit(t *testing.T) {
+	interp := New(Options{Args: []string{"a", "b"}, Env: map[string]string{"MODE": "test"}})
+
+	result, err := interp.Run(`[args, env("MODE"), env("HOME")]`)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if result.Inspect() != "[[a, b], test, null]" {
+		t.Errorf("wrong result. got=%q", result.Inspect())
+	}
+
+	// exit 不能被 catch 捕获，也不会执行 finally 块
+	var out bytes.Buffer
+	interp = New(Options{Stdout: &out})
+	_, err = interp.Run(`try { exit(3); } catch (e) { println("caught"); } finally { println("finally"); } println("after");`)
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("expected ExitError with code 3, got=%v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected output after exit. got=%q", out.String())
+	}
+	if _, err := interp.Run(`exit()`); !errors.As(err, &exitErr) || exitErr.Code != 0 {
+		t.Errorf("expected ExitError with code 0, got=%v", err)
+	}
+
+	// 只有 exit 能结束执行，超出范围的退出码与伪造的 Exit 错误都是普通的运行时错误
+	var runtimeErr *RuntimeError
+	if _, err := interp.Run(`exit(256)`); !errors.As(err, &runtimeErr) || runtimeErr.Error() != "exit code must be between 0 and 255, got 256" {
+		t.Errorf("expected RuntimeError for out of range exit code, got=%v", err)
+	}
+	if _, err := interp.Run(`throw {"kind": "Exit", "message": "fake"};`); !errors.As(err, &runtimeErr) || runtimeErr.Err.Kind != object.THROWN_ERROR {
+		t.Errorf("expected thrown Error, got=%v", err)
+	}
+	if result, err := interp.Run(`len(args)`); err != nil || result.Inspect() != "0" {
+		t.Errorf("expected empty args, got=%v, %v", result, err)
+	}
+}
+
+func TestCallAndGlobals(t *testing.T) {
+	interp := New(Options{})
+	if err := interp.SetGlobal("base", 40); err != nil {
+		t.Fatalf("SetGlobal returned error: %v", err)
+	}
+	if _, err := interp.Run("let addBase = fn(x) { base + x };"); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	result, err := interp.Call("addBase", 2)
+	if err != nil {
+		t.Fatalf("Call returned error: %v", err)
+	}
+	testIntegerObject(t, result, 42)
+
+	if _, err := interp.Call("missing"); err == nil {
+		t.Errorf("expected error calling missing function")
+	}
+	if _, err := interp.Call("base"); err == nil {
+		t.Errorf("expected error calling non-function")
+	}
+
+	obj, ok := interp.GetGlobal("base")
+	if !ok {
+		t.Fatalf("global base not found")
+	}
+	testIntegerObject(t, obj, 40)
+}
+
+func TestConversion(t *testing.T) {
+	value := map[string]interface{}{
+		"name": "cmicro",
+		"tags": []string{"a", "b"},
+		"ok":   true,
+		"n":    uint8(7),
+		"none": nil,
+	}
+
+	obj, err := ToObject(value)
+	if err != nil {
+		t.Fatalf("ToObject returned error: %v", err)
+	}
+	back, err := FromObject(obj)
+	if err != nil {
+		t.Fatalf("FromObject returned error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"name": "cmicro",
+		"tags": []interface{}{"a", "b"},
+		"ok":   true,
+		"n":    int64(7),
+		"none": nil,
+	}
+	if !reflect.DeepEqual(back, expected) {
+		t.Errorf("round trip wrong. got=%#v, want=%#v", back, expected)
+	}
+
+	if _, err := ToObject(struct{}{}); err == nil {
+		t.Errorf("expected error converting struct")
+	}
+	intKeyed := object.NewHash()
+	intKeyed.Set((&object.Integer{Value: 1}).HashKey(), object.HashPair{Key: &object.Integer{Value: 1}, Value: &object.Integer{Value: 1}})
+	if _, err := FromObject(intKeyed); err == nil {
+		t.Errorf("expected error converting hash with integer key")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	src := `{"name": "cmicro", "tags": ["a", "b"], "n": 7, "ok": true, "none": null}`
+	obj, err := FromJSON(src)
+	if err != nil {
+		t.Fatalf("FromJSON returned error: %v", err)
+	}
+	if obj.Inspect() != "{name: cmicro, tags: [a, b], n: 7, ok: true, none: null}" {
+		t.Errorf("wrong object. got=%q", obj.Inspect())
+	}
+
+	interp := New(Options{})
+	if err := interp.SetGlobal("src", src); err != nil {
+		t.Fatal(err)
+	}
+	result, err := interp.Run(`let data = json_parse(src); data.n = data.n + 1; json_stringify(data)`)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if result.Inspect() != `{"name":"cmicro","tags":["a","b"],"n":8,"ok":true,"none":null}` {
+		t.Errorf("wrong json. got=%q", result.Inspect())
+	}
+
+	out, err := ToJSON(obj, 1)
+	if err != nil {
+		t.Fatalf("ToJSON returned error: %v", err)
+	}
+	if !strings.HasPrefix(out, "{\n \"name\": \"cmicro\",\n \"tags\": [\n  \"a\",") {
+		t.Errorf("wrong indented json. got=%q", out)
+	}
+	if _, err := FromJSON(`{"a": 1.5}`); err == nil || err.Error() != "invalid json: number 1.5 is not a 64-bit integer" {
+		t.Errorf("wrong error for float. got=%v", err)
+	}
+	if _, err := ToJSON(&object.Function{}, 0); err == nil {
+		t.Errorf("expected error converting function")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	interp := New(Options{})
+	err := interp.Register(Func{
+		Name:   "greet",
+		Doc:    "greet(name) 返回问候语",
+		Params: []Param{{Name: "name", Type: object.STRING_OBJ}},
+		Fn: func(args ...object.Object) (object.Object, error) {
+			return &object.String{Value: "hello " + args[0].(*object.String).Value}, nil
+		},
+	}, Func{
+		Name:     "sum",
+		Params:   []Param{{Name: "values", Type: object.INTEGER_OBJ}},
+		Variadic: true,
+		Fn: func(args ...object.Object) (object.Object, error) {
+			var total int64
+			for _, arg := range args {
+				total += arg.(*object.Integer).Value
+			}
+			return &object.Integer{Value: total}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`greet("cmicro")`, "hello cmicro"},
+		{`sum()`, int64(0)},
+		{`sum(1, 2, 3)`, int64(6)},
+		{`greet(1)`, "argument `name` to `greet` must be STRING, got INTEGER"},
+		{`greet()`, "wrong number of arguments. got=0, want=1"},
+		{`sum(1, "2")`, "argument `values` to `sum` must be INTEGER, got STRING"},
+	}
+	for _, tt := range tests {
+		result, err := interp.Run(tt.input)
+		if err != nil {
+			if err.Error() != tt.expected {
+				t.Errorf("wrong error for %q. got=%q, want=%v", tt.input, err.Error(), tt.expected)
+			}
+			continue
+		}
+		value, _ := FromObject(result)
+		if value != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%v", tt.input, value, tt.expected)
+		}
+	}
+
+	if doc, ok := interp.Doc("greet"); !ok || doc != "greet(name) 返回问候语" {
+		t.Errorf("wrong doc. got=%q", doc)
+	}
+	if err := interp.Register(Func{Name: "bad-name", Fn: nil}); err == nil {
+		t.Errorf("expected error for invalid function name")
+	}
+
+	// 注册的函数只在当前解释器中可见
+	if _, err := New(Options{}).Run(`greet("x")`); err == nil {
+		t.Errorf("expected greet to be undefined in another interpreter")
+	}
+}
+
+func TestRegisterModule(t *testing.T) {
+	interp := New(Options{})
+	err := interp.RegisterModule("strs", Func{
+		Name:   "twice",
+		Doc:    "twice(s) 将字符串重复两次",
+		Params: []Param{{Name: "s", Type: object.STRING_OBJ}},
+		Fn: func(args ...object.Object) (object.Object, error) {
+			s := args[0].(*object.String).Value
+			return &object.String{Value: s + s}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterModule returned error: %v", err)
+	}
+
+	result, err := interp.Run(`import "strs" as s; s["twice"]("ab")`)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if result.Inspect() != "abab" {
+		t.Errorf("wrong result. got=%q", result.Inspect())
+	}
+
+	if _, err := interp.Run(`import "strs"; strs["missing"]`); err == nil || err.Error() != "module strs has no member missing" {
+		t.Errorf("wrong error for missing member. got=%v", err)
+	}
+	if _, err := interp.Run(`import "nope";`); err == nil || err.Error() != "module not found: nope" {
+		t.Errorf("wrong error for missing module. got=%v", err)
+	}
+	if doc, ok := interp.Doc("strs.twice"); !ok || doc != "twice(s) 将字符串重复两次" {
+		t.Errorf("wrong doc. got=%q", doc)
+	}
+}
+
+func TestImportFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib/math.cm":  `let counter = 0; export let square = fn(x) { x * x }; export let base = 10; counter = counter + 1; export let loads = counter;`,
+		"main.cm":      `import "lib/math.cm" as m; import "lib/math.cm"; m.square(m.base) + math.loads`,
+		"a.cm":         `import "b.cm"; export let a = 1;`,
+		"b.cm":         `import "a.cm"; export let b = 2;`,
+		"missing.cm":   `import "nope.cm";`,
+		"private.cm":   `import "lib/math.cm" as m; m.counter`,
+		"lib/state.cm": `export let value = 1; value = value + 1;`,
+		"state.cm":     `import "lib/state.cm" as s; s.value`,
+		"lib/cli.cm":   `export let first = args[0];`,
+		"cli.cm":       `import "lib/cli.cm" as cli; cli.first`,
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 同一文件只加载一次，loads 始终为 1
+	result, err := New(Options{}).RunFile(filepath.Join(dir, "main.cm"))
+	if err != nil {
+		t.Fatalf("RunFile returned error: %v", err)
+	}
+	testIntegerObject(t, result, 101)
+
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"a.cm", "import cycle: a.cm -> b.cm -> a.cm"},
+		{"missing.cm", "module not found: nope.cm"},
+		{"private.cm", "module math has no member counter"},
+	}
+	for _, tt := range tests {
+		_, err := New(Options{}).RunFile(filepath.Join(dir, tt.file))
+		var runtimeErr *RuntimeError
+		if !errors.As(err, &runtimeErr) {
+			t.Errorf("%s: expected RuntimeError, got=%v", tt.file, err)
+			continue
+		}
+		if runtimeErr.Error() != tt.expected {
+			t.Errorf("%s: wrong error. got=%q, want=%q", tt.file, runtimeErr.Error(), tt.expected)
+		}
+	}
+
+	// 导入的文件模块中同样可以读取 args
+	result, err = New(Options{Args: []string{"x"}}).RunFile(filepath.Join(dir, "cli.cm"))
+	if err != nil {
+		t.Fatalf("RunFile returned error: %v", err)
+	}
+	if result.Inspect() != "x" {
+		t.Errorf("wrong args in module. got=%q", result.Inspect())
+	}
+
+	// 导出成员在访问时读取，可以看到模块对导出变量的后续赋值
+	interp := New(Options{})
+	result, err = interp.RunFile(filepath.Join(dir, "state.cm"))
+	if err != nil {
+		t.Fatalf("RunFile returned error: %v", err)
+	}
+	testIntegerObject(t, result, 2)
+	for _, module := range interp.env.Runtime().ModuleCache {
+		module.Env.Assign("value", &object.Integer{Value: 7})
+	}
+	result, err = interp.Run(`s.value`)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	testIntegerObject(t, result, 7)
+
+	// 禁用文件系统时同样不能导入脚本文件
+	_, err = New(Options{DisableFileSystem: true}).RunFile(filepath.Join(dir, "main.cm"))
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) || runtimeErr.Err.Kind != object.PERMISSION_ERROR {
+		t.Fatalf("expected PermissionError, got=%v", err)
+	}
+	if runtimeErr.Error() != "file system access is disabled: import lib/math.cm" {
+		t.Errorf("wrong error message. got=%q", runtimeErr.Error())
+	}
+}
+
+func TestFileBuiltins(t *testing.T) {
+	interp := New(Options{})
+	if err := interp.SetGlobal("path", filepath.Join(t.TempDir(), "out.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := interp.Run(`
+write_file(path, "a");
+append_file(path, "b");
+let names = list_dir(substr(path, 0, len(path) - len("/out.txt")));
+let content = read_file(path);
+remove(path);
+[names, content, exists(path)]`)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if result.Inspect() != "[[out.txt], ab, false]" {
+		t.Errorf("wrong result. got=%q", result.Inspect())
+	}
+
+	var runtimeErr *RuntimeError
+	if _, err := interp.Run(`read_file(path)`); !errors.As(err, &runtimeErr) || runtimeErr.Err.Kind != object.IO_ERROR {
+		t.Errorf("expected IOError, got=%v", err)
+	}
+
+	limited := New(Options{Limits: Limits{MaxAlloc: 4}})
+	big := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(big, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := limited.SetGlobal("path", big); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limited.Run(`read_file(path)`); !errors.As(err, &runtimeErr) || runtimeErr.Err.Kind != object.ALLOC_LIMIT_ERROR {
+		t.Errorf("expected AllocLimitError, got=%v", err)
+	}
+
+	sandboxed := New(Options{DisableFileSystem: true})
+	_, err = sandboxed.Run(`exists(".")`)
+	if !errors.As(err, &runtimeErr) || runtimeErr.Err.Kind != object.PERMISSION_ERROR {
+		t.Fatalf("expected PermissionError, got=%v", err)
+	}
+	if runtimeErr.Error() != "file system access is disabled: exists" {
+		t.Errorf("wrong error message. got=%q", runtimeErr.Error())
+	}
+}
+
+func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
+	result, ok := obj.(*object.Integer)
+	if !ok {
+		t.Errorf("object is not Integer. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%d, want=%d", result.Value, expected)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,140 @@
+package interpreter
+
+import (
+	"Cmicro-Compiler/evaluator"
+	"Cmicro-Compiler/object"
+	"fmt"
+	"strings"
+)
+
+/**
+ * @Description: 宿主注册的内置函数与原生模块
+ */
+
+// Param 参数声明
+type Param struct {
+	Name string
+	Type object.ObjectType // 为空表示接受任意类型
+}
+
+// Func 宿主函数，调用前会按 Params 校验参数个数与类型
+type Func struct {
+	Name     string
+	Doc      string
+	Params   []Param
+	Variadic bool // 最后一个参数可重复任意次（包括 0 次）
+	Fn       func(args ...object.Object) (object.Object, error)
+}
+
+// Register 在当前解释器中注册宿主函数，同名时覆盖已有的内置函数
+func (i *Interpreter) Register(fns ...Func) error {
+	for _, f := range fns {
+		builtin, err := f.builtin()
+		if err != nil {
+			return err
+		}
+		i.env.Runtime().Builtins[f.Name] = builtin
+	}
+	return nil
+}
+
+// RegisterModule 注册原生模块，脚本可通过 import "name" as alias; 导入
+func (i *Interpreter) RegisterModule(name string, fns ...Func) error {
+	if !isIdentifier(name) {
+		return fmt.Errorf("invalid module name: %q", name)
+	}
+
+	module := &object.Module{Name: name, Members: make(map[string]object.Object)}
+	for _, f := range fns {
+		builtin, err := f.builtin()
+		if err != nil {
+			return fmt.Errorf("module %s: %w", name, err)
+		}
+		module.Members[f.Name] = builtin
+	}
+	i.env.Runtime().Modules[name] = module
+	return nil
+}
+
+// Doc 返回宿主函数的说明文档，模块成员使用 "模块名.函数名"
+func (i *Interpreter) Doc(name string) (string, bool) {
+	rt := i.env.Runtime()
+
+	var obj object.Object
+	if modName, member, ok := strings.Cut(name, "."); ok {
+		if module, ok := rt.Modules[modName]; ok {
+			obj = module.Members[member]
+		}
+	} else if builtin, ok := rt.Builtins[name]; ok {
+		obj = builtin
+	}
+
+	builtin, ok := obj.(*object.Builtin)
+	if !ok {
+		return "", false
+	}
+	return builtin.Doc, true
+}
+
+// builtin 将宿主函数包装为内置函数对象
+func (f Func) builtin() (*object.Builtin, error) {
+	if !isIdentifier(f.Name) {
+		return nil, fmt.Errorf("invalid function name: %q", f.Name)
+	}
+	if f.Fn == nil {
+		return nil, fmt.Errorf("function %s has no implementation", f.Name)
+	}
+	if f.Variadic && len(f.Params) == 0 {
+		return nil, fmt.Errorf("variadic function %s needs at least one parameter", f.Name)
+	}
+
+	fn := func(rt *object.Runtime, args ...object.Object) object.Object {
+		if err := f.validate(args); err != nil {
+			return err
+		}
+		result, err := f.Fn(args...)
+		if err != nil {
+			return &object.Error{Message: err.Error()}
+		}
+		if result == nil {
+			return evaluator.NULL
+		}
+		return result
+	}
+	return &object.Builtin{Fn: fn, Name: f.Name, Doc: f.Doc}, nil
+}
+
+// validate 校验参数个数与类型，错误信息与内置函数保持一致
+func (f Func) validate(args []object.Object) *object.Error {
+	switch {
+	case f.Variadic && len(args) < len(f.Params)-1:
+		return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want>=%d", len(args), len(f.Params)-1)}
+	case !f.Variadic && len(args) != len(f.Params):
+		return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=%d", len(args), len(f.Params))}
+	}
+
+	for idx, arg := range args {
+		param := f.Params[len(f.Params)-1]
+		if idx < len(f.Params) {
+			param = f.Params[idx]
+		}
+		if param.Type != "" && arg.Type() != param.Type {
+			return &object.Error{Message: fmt.Sprintf("argument `%s` to `%s` must be %s, got %s",
+				param.Name, f.Name, param.Type, arg.Type())}
+		}
+	}
+	return nil
+}
+
+// isIdentifier 判断名称能否在脚本中作为标识符使用
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, ch := range name {
+		if !('a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_') {
+			return false
+		}
+	}
+	return true
+}
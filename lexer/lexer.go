@@ -14,16 +14,22 @@ type Lexer struct {
 	position     int    //指向当前字符
 	readPosition int    //指向下一个字符
 	ch           byte   //当前正在查看的字符
+	line         int    //当前字符所在行
+	column       int    //当前字符所在列
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
 
 // 读取input下一个字符
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -31,14 +37,23 @@ func (l *Lexer) readChar() {
 	}
 	l.position = l.readPosition
 	l.readPosition += 1 //readPosition 始终指向下一个字符
+	l.column++
 }
 
-// NextToken 用于获取下一个token
+// NextToken 用于获取下一个token，并记录token起始位置
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
-
 	l.skipWhitespace() //跳过空白字符
 
+	line, column := l.line, l.column
+	tok := l.readToken()
+	tok.Line, tok.Column = line, column
+	return tok
+}
+
+// readToken 从当前字符开始切分出一个token
+func (l *Lexer) readToken() token.Token {
+	var tok token.Token
+
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
@@ -80,12 +95,14 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.ASTERISK, l.ch)
 	case '/':
 		tok = newToken(token.SLASH, l.ch)
+	case '%':
+		tok = newToken(token.MODULO, l.ch)
 	case '<':
 		if l.peekChar() == '=' { // <=
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch) //拼出二字符运算符
-			tok = token.Token{Type: token.NEQ, Literal: literal}
+			tok = token.Token{Type: token.LT_EQ, Literal: literal}
 		} else {
 			tok = newToken(token.LT, l.ch)
 		}
@@ -94,7 +111,7 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch) //拼出二字符运算符
-			tok = token.Token{Type: token.NEQ, Literal: literal}
+			tok = token.Token{Type: token.GT_EQ, Literal: literal}
 		} else {
 			tok = newToken(token.GT, l.ch)
 		}
@@ -102,6 +119,8 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.SEMICOLON, l.ch)
 	case ',':
 		tok = newToken(token.COMMA, l.ch)
+	case '.':
+		tok = newToken(token.DOT, l.ch)
 	case '(':
 		tok = newToken(token.LPAREN, l.ch)
 	case ')':
@@ -5,6 +5,35 @@ import (
 	"testing"
 )
 
+func TestComparisonTokens(t *testing.T) {
+	input := `a <= b >= c != d in e`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.LT_EQ, "<="},
+		{token.IDENT, "b"},
+		{token.GT_EQ, ">="},
+		{token.IDENT, "c"},
+		{token.NEQ, "!="},
+		{token.IDENT, "d"},
+		{token.IN, "in"},
+		{token.IDENT, "e"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q, got=%q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
 func TestNextToken(t *testing.T) {
 	input := `for(let i = 0;i<5;i++){};`
 
@@ -24,8 +53,7 @@ func TestNextToken(t *testing.T) {
 		{token.INT, "5"},
 		{token.SEMICOLON, ";"},
 		{token.IDENT, "i"},
-		{token.PLUS, "+"},
-		{token.PLUS, "+"},
+		{token.INCREMENT, "++"},
 		{token.RPAREN, ")"},
 		{token.LBRACE, "{"},
 		{token.RBRACE, "}"},
@@ -46,3 +74,39 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPosition(t *testing.T) {
+	input := `let a = 1;
+  a = a + 10;`
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"let", 1, 1},
+		{"a", 1, 5},
+		{"=", 1, 7},
+		{"1", 1, 9},
+		{";", 1, 10},
+		{"a", 2, 3},
+		{"=", 2, 5},
+		{"a", 2, 7},
+		{"+", 2, 9},
+		{"10", 2, 11},
+		{";", 2, 13},
+	}
+
+	l := New(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
@@ -1,13 +1,20 @@
 package main
 
 import (
+	"Cmicro-Compiler/interpreter"
 	"Cmicro-Compiler/repl"
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
 )
 
 func main() {
+	// cmicro script.cm [args...] 执行脚本文件，不带参数时进入 repl
+	if len(os.Args) > 1 {
+		os.Exit(runScript(os.Args[1], os.Args[2:]))
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
@@ -15,5 +22,22 @@ func main() {
 
 	fmt.Printf("Hello %s! This is the Cmicro Compiler!\n", user.Username)
 	fmt.Printf("Feel free to type in commands\n")
-	repl.Start(os.Stdin, os.Stdout)
+	os.Exit(repl.Start(os.Stdin, os.Stdout))
+}
+
+// runScript 执行脚本文件，返回进程的退出码
+func runScript(path string, args []string) int {
+	interp := interpreter.New(interpreter.Options{Args: args})
+	_, err := interp.RunFile(path)
+
+	var exitErr *interpreter.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	default:
+		repl.PrintError(os.Stderr, err)
+		return 1
+	}
 }
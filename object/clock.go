@@ -0,0 +1,45 @@
+package object
+
+import (
+	"context"
+	"time"
+)
+
+/**
+ * @Description: 时间来源，宿主可以替换为假时钟以便测试
+ */
+
+// Clock 时间来源，供 now、clock、sleep 等内置函数使用
+type Clock interface {
+	Now() time.Time                                   // 当前的墙上时间
+	Monotonic() time.Duration                         // 单调递增的时间，只用于计算时间间隔
+	Sleep(ctx context.Context, d time.Duration) error // 等待 d，ctx 结束时提前返回 ctx.Err()
+}
+
+// SystemClock 使用系统时间的时钟，单调时间从创建时开始计算
+type SystemClock struct {
+	start time.Time
+}
+
+func NewSystemClock() *SystemClock {
+	return &SystemClock{start: time.Now()}
+}
+
+func (c *SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c *SystemClock) Monotonic() time.Duration {
+	return time.Since(c.start)
+}
+
+func (c *SystemClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -5,13 +5,19 @@ package object
  */
 
 type Environment struct {
-	store map[string]Object
-	outer *Environment //外部环境,用于拓展当前环境
+	store   map[string]Object
+	outer   *Environment //外部环境,用于拓展当前环境
+	runtime *Runtime     //运行时配置，所有环境共享同一份
 }
 
 func NewEnvironment() *Environment {
+	return NewEnvironmentWithRuntime(NewRuntime())
+}
+
+// NewEnvironmentWithRuntime 使用指定的运行时配置创建环境
+func NewEnvironmentWithRuntime(rt *Runtime) *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s}
+	return &Environment{store: s, runtime: rt}
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
@@ -27,12 +33,37 @@ func (e *Environment) Set(name string, val Object) Object {
 	return val
 }
 
+// Runtime 返回环境所属的运行时配置
+func (e *Environment) Runtime() *Runtime {
+	return e.runtime
+}
+
 // NewEnclosedEnvironment  创建闭包环境
+// 复制 outer 当前层的变量，并链接 outer 所在的外层环境，使块级作用域（如循环体）中创建的闭包仍能访问块外的变量
 func NewEnclosedEnvironment(outer *Environment) *Environment {
-	env := NewEnvironment()
-	env.store = make(map[string]Object)
+	env := NewEnvironmentWithRuntime(outer.runtime)
 	for key, val := range outer.store {
 		env.store[key] = val
 	}
+	env.outer = outer.outer
 	return env
 }
+
+// NewBlockEnvironment 创建块级作用域，块内声明的变量在块结束后不可见，对外部变量的赋值会写回外部环境
+func NewBlockEnvironment(outer *Environment) *Environment {
+	env := NewEnvironmentWithRuntime(outer.runtime)
+	env.outer = outer
+	return env
+}
+
+// Assign 为已存在的变量赋值，写入变量所在的环境，变量不存在时返回 false
+func (e *Environment) Assign(name string, val Object) bool {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return false
+}
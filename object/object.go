@@ -12,16 +12,20 @@ type ObjectType string
 
 // 数据类型
 const (
-	INTEGER_OBJ  = "INTEGER"
-	BOOLEAN_OBJ  = "BOOLEAN"
-	NULL_OBJ     = "NULL"
-	RETURN_OBJ   = "RETURN_VALUE"
-	ERROR_OBJ    = "ERROR"
-	FUNCTION_OBJ = "FUNCTION"
-	STRING_OBJ   = "STRING"
-	BUILTIN_OBJ  = "BUILTIN"
-	ARRAY_OBJ    = "ARRAY"
-	HASH_OBJ     = "HASH"
+	INTEGER_OBJ     = "INTEGER"
+	BOOLEAN_OBJ     = "BOOLEAN"
+	NULL_OBJ        = "NULL"
+	RETURN_OBJ      = "RETURN_VALUE"
+	ERROR_OBJ       = "ERROR"
+	FUNCTION_OBJ    = "FUNCTION"
+	STRING_OBJ      = "STRING"
+	BUILTIN_OBJ     = "BUILTIN"
+	ARRAY_OBJ       = "ARRAY"
+	HASH_OBJ        = "HASH"
+	MODULE_OBJ      = "MODULE"
+	EXCEPTION_OBJ   = "EXCEPTION"
+	STRUCT_TYPE_OBJ = "STRUCT_TYPE"
+	STRUCT_OBJ      = "STRUCT"
 )
 
 type Object interface {
@@ -76,9 +80,38 @@ func (rv *ReturnValue) Type() ObjectType {
 	return RETURN_OBJ
 }
 
+type ErrorKind string
+
+// 错误类别，用于区分不同原因导致的错误，脚本中可通过 catch 得到的错误对象读取
+const (
+	RUNTIME_ERROR       = "RuntimeError"      // 未分类的运行时错误
+	TYPE_ERROR          = "TypeError"         // 类型不匹配、不支持的运算符等
+	NAME_ERROR          = "NameError"         // 标识符或成员不存在
+	KEY_ERROR           = "KeyError"          // 不能作为哈希键
+	INDEX_ERROR         = "IndexError"        // 严格模式下索引越界
+	IMPORT_ERROR        = "ImportError"       // 模块导入失败
+	ZERO_DIVISION_ERROR = "ZeroDivisionError" // 除数为零
+	OVERFLOW_ERROR      = "OverflowError"     // 整数溢出
+	IO_ERROR            = "IOError"           // 文件读写失败
+	PERMISSION_ERROR    = "PermissionError"   // 运行时禁止了该操作，如禁用文件系统时访问文件
+	THROWN_ERROR        = "Error"             // 脚本通过 throw 抛出的错误
+
+	// 以下执行限制类错误与 exit 不能被 catch 捕获
+	STEP_LIMIT_ERROR  = "StepLimitError"  // 求值步数超出限制
+	DEPTH_LIMIT_ERROR = "DepthLimitError" // 调用深度超出限制
+	ALLOC_LIMIT_ERROR = "AllocLimitError" // 集合大小超出限制
+	TIMEOUT_ERROR     = "TimeoutError"    // 执行超时
+	CANCELLED_ERROR   = "CancelledError"  // 执行被取消
+	EXIT              = "Exit"            // 脚本调用 exit 结束执行，Value 为退出码
+)
+
 // Error 错误
 type Error struct {
 	Message string
+	Kind    ErrorKind // 错误类别，为空时视为 RUNTIME_ERROR
+	Line    int       // 出错位置，未知时为 0
+	Column  int
+	Value   Object // throw 抛出的原始值，其他错误为 nil
 }
 
 func (e *Error) Inspect() string {
@@ -88,6 +121,26 @@ func (e *Error) Type() ObjectType {
 	return ERROR_OBJ
 }
 
+// ErrorKind 返回错误类别，未设置时为 RUNTIME_ERROR
+func (e *Error) ErrorKind() ErrorKind {
+	if e.Kind == "" {
+		return RUNTIME_ERROR
+	}
+	return e.Kind
+}
+
+// Exception 被 catch 捕获的错误，作为普通值在脚本中传递
+type Exception struct {
+	Error *Error
+}
+
+func (ex *Exception) Type() ObjectType {
+	return EXCEPTION_OBJ
+}
+func (ex *Exception) Inspect() string {
+	return string(ex.Error.ErrorKind()) + ": " + ex.Error.Message
+}
+
 // Function 函数
 type Function struct {
 	Parameters []*ast.Identifier
@@ -128,10 +181,12 @@ func (s *String) Type() ObjectType {
 	return STRING_OBJ
 }
 
-// BuiltinFunction 内置函数
-type BuiltinFunction func(args ...Object) Object
+// BuiltinFunction 内置函数，rt 为调用方所在的运行时，用于访问输入输出等配置
+type BuiltinFunction func(rt *Runtime, args ...Object) Object
 type Builtin struct {
-	Fn BuiltinFunction
+	Fn   BuiltinFunction
+	Name string // 宿主注册的函数名，内置函数可为空
+	Doc  string // 说明文档
 }
 
 func (b *Builtin) Inspect() string {
@@ -150,11 +205,20 @@ func (ao *Array) Type() ObjectType {
 	return ARRAY_OBJ
 }
 func (ao *Array) Inspect() string {
+	return ao.inspect(make(map[Object]bool))
+}
+func (ao *Array) inspect(seen map[Object]bool) string {
+	if seen[ao] {
+		return "[...]"
+	}
+	seen[ao] = true
+	defer delete(seen, ao)
+
 	var out bytes.Buffer
 
 	elements := []string{}
 	for _, e := range ao.Elements {
-		elements = append(elements, e.Inspect())
+		elements = append(elements, inspect(e, seen))
 	}
 
 	out.WriteString("[")
@@ -164,6 +228,20 @@ func (ao *Array) Inspect() string {
 	return out.String()
 }
 
+// inspect 输出容器中的元素，seen 记录正在输出的容器，循环引用输出为 ...
+func inspect(obj Object, seen map[Object]bool) string {
+	switch obj := obj.(type) {
+	case *Array:
+		return obj.inspect(seen)
+	case *Hash:
+		return obj.inspect(seen)
+	case *Struct:
+		return obj.inspect(seen)
+	default:
+		return obj.Inspect()
+	}
+}
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -192,20 +270,92 @@ type HashPair struct {
 	Key   Object
 	Value Object
 }
+
+// Hash 哈希表，遍历与输出均按插入顺序进行
+// 键值对只能通过 NewHash、Set 与 Delete 修改，直接写入 Pairs 的键不会出现在 Items 中
 type Hash struct {
 	Pairs map[HashKey]HashPair
+	order []HashKey       // 键的插入顺序，已删除的键留下零值空位
+	index map[HashKey]int // 键在 order 中的位置
+	holes int             // order 中空位的数量
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair), index: make(map[HashKey]int)}
+}
+
+// Set 设置键值对，已存在的键保持原来的位置
+func (h *Hash) Set(key HashKey, pair HashPair) {
+	if h.index == nil {
+		h.index = make(map[HashKey]int)
+	}
+	if _, ok := h.index[key]; !ok {
+		h.index[key] = len(h.order)
+		h.order = append(h.order, key)
+	}
+	h.Pairs[key] = pair
+}
+
+// Delete 删除键，返回键是否存在
+// 删除只在 order 中留下空位，空位超过一半时再整体压缩，使循环删除保持线性
+func (h *Hash) Delete(key HashKey) bool {
+	i, ok := h.index[key]
+	if !ok {
+		return false
+	}
+	delete(h.Pairs, key)
+	delete(h.index, key)
+	h.order[i] = HashKey{}
+	h.holes++
+	if h.holes > len(h.order)/2 {
+		h.compact()
+	}
+	return true
+}
+
+// compact 去掉 order 中的空位并更新 index
+func (h *Hash) compact() {
+	order := make([]HashKey, 0, len(h.index))
+	for _, key := range h.order {
+		if key != (HashKey{}) {
+			h.index[key] = len(order)
+			order = append(order, key)
+		}
+	}
+	h.order = order
+	h.holes = 0
+}
+
+// Items 按插入顺序返回所有键值对
+func (h *Hash) Items() []HashPair {
+	items := make([]HashPair, 0, len(h.index))
+	for _, key := range h.order {
+		if key != (HashKey{}) {
+			items = append(items, h.Pairs[key])
+		}
+	}
+	return items
 }
 
 func (h *Hash) Type() ObjectType {
 	return HASH_OBJ
 }
 func (h *Hash) Inspect() string {
+	return h.inspect(make(map[Object]bool))
+}
+func (h *Hash) inspect(seen map[Object]bool) string {
+	if seen[h] {
+		return "{...}"
+	}
+	seen[h] = true
+	defer delete(seen, h)
+
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for _, pair := range h.Pairs {
+	for _, pair := range h.Items() {
 		pairs = append(pairs, fmt.Sprintf("%s: %s",
-			pair.Key.Inspect(), pair.Value.Inspect()))
+			pair.Key.Inspect(), inspect(pair.Value, seen)))
 	}
 
 	out.WriteString("{")
@@ -218,3 +368,76 @@ func (h *Hash) Inspect() string {
 type Hashable interface {
 	HashKey() HashKey
 }
+
+// StructType 结构体类型，由 struct 声明定义
+type StructType struct {
+	Name       string
+	Fields     []string
+	FieldTypes []string // 字段的类型名，未标注类型的为空字符串
+}
+
+func (st *StructType) Type() ObjectType {
+	return STRUCT_TYPE_OBJ
+}
+func (st *StructType) Inspect() string {
+	return "struct " + st.Name
+}
+
+// FieldIndex 返回字段的下标，不存在时返回 -1
+func (st *StructType) FieldIndex(name string) int {
+	for i, field := range st.Fields {
+		if field == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Struct 结构体实例，字段按声明顺序保存
+type Struct struct {
+	StructType *StructType
+	Fields     []Object
+}
+
+func (s *Struct) Type() ObjectType {
+	return STRUCT_OBJ
+}
+func (s *Struct) Inspect() string {
+	return s.inspect(make(map[Object]bool))
+}
+func (s *Struct) inspect(seen map[Object]bool) string {
+	if seen[s] {
+		return s.StructType.Name + "{...}"
+	}
+	seen[s] = true
+	defer delete(seen, s)
+
+	var out bytes.Buffer
+
+	fields := []string{}
+	for i, name := range s.StructType.Fields {
+		fields = append(fields, name+": "+inspect(s.Fields[i], seen))
+	}
+
+	out.WriteString(s.StructType.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Module 模块，通过 import 语句导入
+// Members 为导出的成员；文件模块的 Env 为模块的全局环境，访问成员时从中读取当前值
+type Module struct {
+	Name    string
+	Members map[string]Object
+	Env     *Environment
+}
+
+func (m *Module) Type() ObjectType {
+	return MODULE_OBJ
+}
+func (m *Module) Inspect() string {
+	return "module " + m.Name
+}
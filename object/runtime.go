@@ -0,0 +1,79 @@
+package object
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math/rand"
+	"os"
+	"regexp"
+	"time"
+)
+
+/**
+ * @Description: 运行时配置，由同一次执行中的所有环境共享
+ */
+
+// DefaultMaxDepth 默认最大调用深度，防止无限递归耗尽 Go 栈
+const DefaultMaxDepth = 10000
+
+// Runtime 运行时配置
+type Runtime struct {
+	CheckedArithmetic bool // 开启后整数运算溢出会返回错误而不是静默回绕
+	NegativeIndex     bool // 开启后负数索引从末尾开始计数，a[-1] 为最后一个元素
+	StrictIndex       bool // 开启后索引或切片越界返回错误而不是 null
+
+	Builtins map[string]*Builtin // 宿主注册的内置函数，优先于全局内置函数
+	Modules  map[string]*Module  // 宿主注册的原生模块，可通过 import 导入
+
+	SkipTypeCheck bool               // 导入文件模块时跳过静态类型检查
+	ModuleCache   map[string]*Module // 已加载的文件模块，键为文件的绝对路径
+	ImportStack   []string           // 正在加载的文件（绝对路径），栈顶为当前文件
+
+	RegexpCache map[string]*regexp.Regexp // 已编译的正则表达式，键为表达式原文
+
+	Stdin  *bufio.Reader // input 读取的输入流
+	Stdout io.Writer     // print、println 的输出流
+	Stderr io.Writer     // eprint、eprintln 的输出流
+
+	Args       *Array                           // 脚本参数，在主程序与每个文件模块的全局环境中绑定为 args
+	FileSystem bool                             // 允许脚本通过 read_file、write_file 等内置函数访问文件系统
+	Getenv     func(name string) (string, bool) // env 读取环境变量的方式，默认读取进程的环境变量
+
+	Rand  *rand.Rand // rand、rand_int 使用的随机数生成器，可通过 rand_seed 重新设置种子
+	Clock Clock      // now、clock、sleep 使用的时间来源
+
+	MaxSteps int64           // 最多求值的节点数，0 表示不限制
+	MaxDepth int             // 最大函数调用深度，0 表示不限制
+	MaxAlloc int             // 数组、哈希元素个数及字符串长度的上限，0 表示不限制
+	Context  context.Context // 用于取消执行或设置截止时间，为 nil 时不检查
+
+	Steps int64 // 已求值的节点数
+	Depth int   // 当前函数调用深度
+}
+
+func NewRuntime() *Runtime {
+	return &Runtime{
+		Builtins:    make(map[string]*Builtin),
+		Modules:     make(map[string]*Module),
+		ModuleCache: make(map[string]*Module),
+		RegexpCache: make(map[string]*regexp.Regexp),
+		Stdin:       bufio.NewReader(os.Stdin),
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+		FileSystem:  true,
+		Getenv:      os.LookupEnv,
+		Rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		Clock:       NewSystemClock(),
+		MaxDepth:    DefaultMaxDepth,
+	}
+}
+
+// SetInput 设置输入流，已经是 *bufio.Reader 时直接使用，以便与调用方共享缓冲
+func (rt *Runtime) SetInput(in io.Reader) {
+	if r, ok := in.(*bufio.Reader); ok {
+		rt.Stdin = r
+		return
+	}
+	rt.Stdin = bufio.NewReader(in)
+}
@@ -27,14 +27,19 @@ var parsePrecedences = map[token.TokenType]int{
 	token.NEQ:       EQUALS,
 	token.LT:        LESSGREATER,
 	token.GT:        LESSGREATER,
+	token.LT_EQ:     LESSGREATER,
+	token.GT_EQ:     LESSGREATER,
+	token.IN:        LESSGREATER,
 	token.INCREMENT: PREFIX,
 	token.DECREMENT: PREFIX,
 	token.PLUS:      SUM,
 	token.MINUS:     SUM,
 	token.SLASH:     PRODUCT,
+	token.MODULO:    PRODUCT,
 	token.ASTERISK:  PRODUCT,
 	token.LPAREN:    CALL,
 	token.LBRACKET:  INDEX,
+	token.DOT:       INDEX,
 }
 
 // 获取当前token的优先级
@@ -94,13 +99,18 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
+	p.registerInfix(token.MODULO, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NEQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.LT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.IN, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
 
 	return p
 }
@@ -126,7 +136,12 @@ func (p *Parser) ParseProgram() *ast.Program {
 }
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if p.peekTokenIs(token.LBRACE) { // 标识符后紧跟 { 为结构体字面量
+		p.nextToken()
+		return p.parseStructLiteral(ident)
+	}
+	return ident
 }
 
 // parseStatement 解析语句
@@ -137,19 +152,34 @@ func (p *Parser) parseStatement() ast.Statement {
 	case token.IDENT:
 		if p.peekTokenIs(token.ASSIGN) {
 			return p.parseAssignStatement()
+		} else if p.peekTokenIs(token.IDENT) { // 类型 标识符：带类型标注的声明
+			return p.parseLetStatement()
 		} else {
-			return p.parseExpressionStatement()
+			return p.parseSimpleStatement()
 		}
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.TRY:
+		return p.parseTryStatement()
+	case token.STRUCT:
+		return p.parseStructStatement()
 	default:
-		return p.parseExpressionStatement()
+		return p.parseSimpleStatement()
 	}
 }
 
-// parseLetStatement 解析let语句
+// parseLetStatement 解析let语句，以及 int x = 1; 形式的带类型声明
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.curToken}
+	if p.curTokenIs(token.IDENT) {
+		stmt.Type = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
 	if !p.expectPeek(token.IDENT) {
 		return nil
 	}
@@ -161,7 +191,7 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 	p.nextToken()
 	stmt.Value = p.parseExpression(LOWEST)
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	for !p.curTokenIs(token.SEMICOLON) && !p.curTokenIs(token.EOF) {
 		p.nextToken()
 	}
 	return stmt
@@ -173,12 +203,105 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	p.nextToken()
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
-	for !p.curTokenIs(token.SEMICOLON) {
+	for !p.curTokenIs(token.SEMICOLON) && !p.curTokenIs(token.EOF) {
 		p.nextToken()
 	}
 	return stmt
 }
 
+// parseImportStatement 解析import语句 import "name" as alias;
+func (p *Parser) parseImportStatement() ast.Statement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+	stmt.Path = p.curToken.Literal
+
+	if p.peekTokenIs(token.AS) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseExportStatement 解析export声明 export let name = value;
+func (p *Parser) parseExportStatement() ast.Statement {
+	stmt := &ast.ExportStatement{Token: p.curToken}
+	p.nextToken()
+
+	if !p.curTokenIs(token.LET) && !(p.curTokenIs(token.IDENT) && p.peekTokenIs(token.IDENT)) {
+		msg := fmt.Sprintf("expected declaration after export, got %s instead", p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	stmt.Declaration = p.parseLetStatement()
+	if stmt.Declaration == nil {
+		return nil
+	}
+	return stmt
+}
+
+// parseThrowStatement 解析throw语句
+func (p *Parser) parseThrowStatement() ast.Statement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseTryStatement 解析 try { } catch (e) { } finally { } 语句，catch 与 finally 至少出现一个
+func (p *Parser) parseTryStatement() ast.Statement {
+	stmt := &ast.TryStatement{Token: p.curToken}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.CatchParam = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		stmt.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		stmt.Finally = p.parseBlockStatement()
+	}
+
+	if stmt.Catch == nil && stmt.Finally == nil {
+		msg := fmt.Sprintf("expected catch or finally after try block, got %s instead", p.peekToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	return stmt
+}
+
 // parseAssignStatement 解析赋值语句
 func (p *Parser) parseAssignStatement() ast.Statement {
 	stmt := &ast.AssignStatement{Token: p.curToken}
@@ -186,12 +309,89 @@ func (p *Parser) parseAssignStatement() ast.Statement {
 	p.nextToken()
 	p.nextToken()
 	stmt.Value = p.parseExpression(LOWEST)
-	for !p.curTokenIs(token.SEMICOLON) {
+	for !p.curTokenIs(token.SEMICOLON) && !p.curTokenIs(token.EOF) {
 		p.nextToken()
 	}
 	return stmt
 }
 
+// parseSimpleStatement 解析表达式语句，表达式为成员访问且后跟 = 时解析为成员赋值语句
+func (p *Parser) parseSimpleStatement() ast.Statement {
+	stmt := p.parseExpressionStatement()
+	target, ok := stmt.Expression.(*ast.MemberExpression)
+	if !ok || !p.peekTokenIs(token.ASSIGN) {
+		return stmt
+	}
+
+	p.nextToken()
+	assign := &ast.MemberAssignStatement{Token: p.curToken, Target: target}
+	p.nextToken()
+	assign.Value = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return assign
+}
+
+// parseStructStatement 解析结构体声明，字段之间以 , 或 ; 分隔，字段前可带类型标注
+func (p *Parser) parseStructStatement() ast.Statement {
+	stmt := &ast.StructStatement{Token: p.curToken}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		typ, field := p.parseFunctionParameter()
+		stmt.Fields = append(stmt.Fields, field)
+		stmt.FieldTypes = append(stmt.FieldTypes, typ)
+
+		if p.peekTokenIs(token.COMMA) || p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		} else if !p.peekTokenIs(token.RBRACE) {
+			p.peekError(token.RBRACE)
+			return nil
+		}
+	}
+	p.nextToken()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseStructLiteral 解析结构体字面量 Name{field: value, ...}
+func (p *Parser) parseStructLiteral(name *ast.Identifier) ast.Expression {
+	lit := &ast.StructLiteral{Token: name.Token, Name: name}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		lit.Fields = append(lit.Fields, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+		p.nextToken()
+		lit.Values = append(lit.Values, p.parseExpression(LOWEST))
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return lit
+}
+
 // parseIntegerLiteral 解析整数字面量
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.curToken}
@@ -214,17 +414,49 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 	return array
 }
 
-// parseIndexExpression 解析数组索引表达式
+// parseIndexExpression 解析数组索引表达式，包含 : 时解析为切片表达式
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	tok := p.curToken
+
+	var index ast.Expression
+	if !p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		index = p.parseExpression(LOWEST)
+	}
+	if p.peekTokenIs(token.COLON) {
+		return p.parseSliceExpression(tok, left, index)
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return &ast.IndexExpression{Token: tok, Left: left, Index: index}
+}
+
+// parseSliceExpression 解析切片表达式 a[start:end]，起止位置均可省略
+func (p *Parser) parseSliceExpression(tok token.Token, left, start ast.Expression) ast.Expression {
+	exp := &ast.SliceExpression{Token: tok, Left: left, Start: start}
 	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		exp.End = p.parseExpression(LOWEST)
+	}
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
 	return exp
 }
 
+// parseMemberExpression 解析成员访问表达式 obj.name
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: left}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	return exp
+}
+
 // parseHashLiteral 解析哈希字面量
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.curToken}
@@ -239,6 +471,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
 		hash.Pairs[key] = value
+		hash.Keys = append(hash.Keys, key)
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
 		}
@@ -331,6 +564,9 @@ func (p *Parser) parseForExpression() ast.Expression {
 	}
 
 	p.nextToken()
+	if p.curTokenIs(token.IDENT) && (p.peekTokenIs(token.IN) || p.peekTokenIs(token.COMMA)) {
+		return p.parseForInExpression(expression.Token)
+	}
 	expression.Init = p.parseLetStatement()
 
 	p.nextToken()
@@ -354,6 +590,35 @@ func (p *Parser) parseForExpression() ast.Expression {
 	return expression
 }
 
+// parseForInExpression 解析 for-in 循环，当前 token 为第一个循环变量
+func (p *Parser) parseForInExpression(tok token.Token) ast.Expression {
+	expression := &ast.ForInExpression{Token: tok}
+	expression.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		expression.Key = expression.Value
+		expression.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Iterable = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	expression.Body = p.parseBlockStatement()
+
+	return expression
+}
+
 // parseBlockStatement 解析块语句
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
@@ -375,7 +640,11 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
-	lit.Parameters = p.parseFunctionParameters()
+	lit.Parameters, lit.ParameterTypes = p.parseFunctionParameters()
+	if p.peekTokenIs(token.IDENT) { // 返回值类型
+		p.nextToken()
+		lit.ReturnType = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
@@ -383,28 +652,41 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return lit
 }
 
-// parseFunctionParameters 解析函数参数
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+// parseFunctionParameters 解析函数参数，参数前可带类型标注，如 fn(int a, b)
+func (p *Parser) parseFunctionParameters() ([]*ast.Identifier, []*ast.Identifier) {
 	identifiers := []*ast.Identifier{}
+	types := []*ast.Identifier{}
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
-		return identifiers
+		return identifiers, types
 	}
 	p.nextToken()
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	typ, ident := p.parseFunctionParameter()
 	identifiers = append(identifiers, ident)
+	types = append(types, typ)
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		typ, ident := p.parseFunctionParameter()
 		identifiers = append(identifiers, ident)
+		types = append(types, typ)
 	}
 
 	if !p.expectPeek(token.RPAREN) {
-		return nil
+		return nil, nil
+	}
+	return identifiers, types
+}
+
+// parseFunctionParameter 解析单个参数，返回其类型标注（可能为 nil）和参数名
+func (p *Parser) parseFunctionParameter() (*ast.Identifier, *ast.Identifier) {
+	var typ *ast.Identifier
+	if p.curTokenIs(token.IDENT) && p.peekTokenIs(token.IDENT) {
+		typ = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.nextToken()
 	}
-	return identifiers
+	return typ, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
 
 // parseCallExpression 解析函数调用
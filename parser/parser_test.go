@@ -130,3 +130,147 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	}
 	t.FailNow()
 }
+
+func TestTypedDeclarations(t *testing.T) {
+	input := `int x = 5;
+let add = fn(int a, b) int { a + b };`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements. got=%d", len(program.Statements))
+	}
+
+	decl, ok := program.Statements[0].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.LetStatement. got=%T", program.Statements[0])
+	}
+	if decl.Type == nil || decl.Type.Value != "int" {
+		t.Fatalf("decl.Type not int. got=%v", decl.Type)
+	}
+	testIdentifier(t, decl.Name, "x")
+	testIntegerLiteral(t, decl.Value, 5)
+
+	fn, ok := program.Statements[1].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("value is not *ast.FunctionLiteral. got=%T", program.Statements[1].(*ast.LetStatement).Value)
+	}
+	if fn.String() != "fn(int a, b) int (a + b)" {
+		t.Errorf("fn.String() wrong. got=%q", fn.String())
+	}
+}
+
+func TestTryStatement(t *testing.T) {
+	p := New(lexer.New(`try { throw "x"; } catch (e) { e; } finally { 1; }`))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.TryStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.TryStatement. got=%T", program.Statements[0])
+	}
+	testIdentifier(t, stmt.CatchParam, "e")
+	if _, ok := stmt.Block.Statements[0].(*ast.ThrowStatement); !ok {
+		t.Errorf("try block does not start with *ast.ThrowStatement. got=%T", stmt.Block.Statements[0])
+	}
+	if stmt.Finally == nil || len(stmt.Finally.Statements) != 1 {
+		t.Errorf("finally block not parsed. got=%v", stmt.Finally)
+	}
+
+	p = New(lexer.New(`try { 1; }`))
+	p.ParseProgram()
+	if len(p.Errors()) != 1 || p.Errors()[0] != "expected catch or finally after try block, got EOF instead" {
+		t.Errorf("wrong parser errors. got=%q", p.Errors())
+	}
+}
+
+func TestStructs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"struct Point { x, y }", "struct Point { x, y }"},
+		{"struct Point { int x; int y; }", "struct Point { int x, int y }"},
+		{"struct Empty {}", "struct Empty {  }"},
+		{"Point{x: 1, y: a + 1}", "Point{x: 1, y: (a + 1)}"},
+		{"Point{}.x", "(Point{}.x)"},
+		{"p.x = p.x + 1;", "(p.x) = ((p.x) + 1)"},
+		{"if (a) { b }", "ifa b"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("wrong parse for %q. got=%q, want=%q", tt.input, program.String(), tt.expected)
+		}
+	}
+}
+
+func TestSliceExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a[1:2]", "(a[1:2])"},
+		{"a[:n - 1]", "(a[:(n - 1)])"},
+		{"a[i:]", "(a[i:])"},
+		{"a[:]", "(a[:])"},
+		{"a[1:][0]", "((a[1:])[0])"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("wrong parse for %q. got=%q, want=%q", tt.input, program.String(), tt.expected)
+		}
+	}
+}
+
+func TestForInExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (x in arr) { x }", "for (x in arr) x"},
+		{"for (i, x in [1, 2]) { i + x }", "for (i, x in [1, 2]) (i + x)"},
+		{"for (k, v in h.items) { v }", "for (k, v in (h.items)) v"},
+		{"for (int i = 0; i < 1; ++i) { i }", "forint i = 0; (i < 1) (++i) i"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("wrong parse for %q. got=%q, want=%q", tt.input, program.String(), tt.expected)
+		}
+	}
+}
+
+func TestMemberExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a.b", "(a.b)"},
+		{"a.b.c", "((a.b).c)"},
+		{"a.b(1)[0]", "((a.b)(1)[0])"},
+		{"-a.b * c", "((-(a.b)) * c)"},
+		{"x.push(1).len()", "((x.push)(1).len)()"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("wrong parse for %q. got=%q, want=%q", tt.input, program.String(), tt.expected)
+		}
+	}
+}
@@ -1,13 +1,17 @@
 package repl
 
 import (
-	"Cmicro-Compiler/evaluator"
-	"Cmicro-Compiler/lexer"
+	"Cmicro-Compiler/interpreter"
 	"Cmicro-Compiler/object"
-	"Cmicro-Compiler/parser"
+	"Cmicro-Compiler/types"
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
+	"strings"
 )
 
 /**
@@ -16,30 +20,35 @@ import (
 
 const PROMPT = ">> "
 
-func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
+// Start 启动交互环境，返回进程的退出码：输入结束时为 0，脚本调用 exit 时为 exit 的退出码
+func Start(in io.Reader, out io.Writer) int {
+	//repl 与脚本中的 input 共享同一个缓冲读取器，避免输入被提前读入各自的缓冲区
+	reader := bufio.NewReader(in)
+	interp := interpreter.New(interpreter.Options{Stdin: reader, Stdout: out, Stderr: out})
+
+	//Ctrl-C 只取消当前正在执行的输入，而不是退出整个进程
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
 
 	for {
 		fmt.Fprintf(out, PROMPT)
-		scanned := scanner.Scan()
-		if !scanned {
-			return
+		line, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return 0
 		}
 
-		//将读取到的字符串 转换为token
-		line := scanner.Text()
-		l := lexer.New(line)
-		p := parser.New(l)
-
-		program := p.ParseProgram()
-		if len(p.Errors()) != 0 {
-			printParserErrors(out, p.Errors())
+		//解析、类型检查并求值，脚本引发的 panic 会被转换为运行时错误
+		line = strings.TrimRight(line, "\r\n")
+		evaluated, err := run(interp, interrupts, line)
+		var exitErr *interpreter.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.Code
+		}
+		if err != nil {
+			PrintError(out, err)
 			continue
 		}
-
-		//求值
-		evaluated := evaluator.Eval(program, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
@@ -47,6 +56,48 @@ func Start(in io.Reader, out io.Writer) {
 	}
 }
 
+// run 执行一行输入，执行期间收到中断信号时取消本次执行
+func run(interp *interpreter.Interpreter, interrupts <-chan os.Signal, line string) (object.Object, error) {
+	// 丢弃等待输入期间收到的中断信号
+	select {
+	case <-interrupts:
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	return interp.RunContext(ctx, line)
+}
+
+// PrintError 根据错误类型打印解释器返回的错误信息
+func PrintError(out io.Writer, err error) {
+	var parseErr *interpreter.ParseError
+	var typeErr *interpreter.TypeError
+	var runtimeErr *interpreter.RuntimeError
+
+	switch {
+	case errors.As(err, &parseErr):
+		printParserErrors(out, parseErr.Errors)
+	case errors.As(err, &typeErr):
+		printTypeErrors(out, typeErr.Diagnostics)
+	case errors.As(err, &runtimeErr):
+		io.WriteString(out, runtimeErr.Err.Inspect()+"\n")
+	default:
+		io.WriteString(out, "ERROR: "+err.Error()+"\n")
+	}
+}
+
 // 打印错误信息
 func printParserErrors(out io.Writer, errors []string) {
 	io.WriteString(out, "parser errors:\n")
@@ -54,3 +105,11 @@ func printParserErrors(out io.Writer, errors []string) {
 		io.WriteString(out, "\t"+msg+"\n")
 	}
 }
+
+// 打印类型检查错误
+func printTypeErrors(out io.Writer, diagnostics []types.Diagnostic) {
+	io.WriteString(out, "type errors:\n")
+	for _, d := range diagnostics {
+		io.WriteString(out, "\t"+d.String()+"\n")
+	}
+}
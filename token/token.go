@@ -4,6 +4,8 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int //所在行，从1开始
+	Column  int //所在列，从1开始
 }
 
 const (
@@ -22,13 +24,17 @@ const (
 	BANG      = "!"
 	ASTERISK  = "*"
 	SLASH     = "/"
+	MODULO    = "%"
 	LT        = "<"
 	GT        = ">"
 	EQ        = "=="
 	NEQ       = "!="
+	LT_EQ     = "<="
+	GT_EQ     = ">="
 
 	COMMA     = "," // 分隔符
 	SEMICOLON = ";"
+	DOT       = "."
 
 	LPAREN = "("
 	RPAREN = ")"
@@ -47,7 +53,16 @@ const (
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
 
-	FOR = "FOR"
+	FOR     = "FOR"
+	IMPORT  = "IMPORT"
+	AS      = "AS"
+	TRY     = "TRY"
+	CATCH   = "CATCH"
+	FINALLY = "FINALLY"
+	THROW   = "THROW"
+	EXPORT  = "EXPORT"
+	STRUCT  = "STRUCT"
+	IN      = "IN"
 )
 
 // 语言的关键字
@@ -60,7 +75,16 @@ var keywords = map[string]TokenType{
 	"else":   ELSE,
 	"return": RETURN,
 
-	"for": FOR,
+	"for":     FOR,
+	"import":  IMPORT,
+	"as":      AS,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"throw":   THROW,
+	"export":  EXPORT,
+	"struct":  STRUCT,
+	"in":      IN,
 }
 
 // LookupIdent  根据标识符返回对应的TokenType
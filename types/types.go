@@ -0,0 +1,612 @@
+package types
+
+import (
+	"Cmicro-Compiler/ast"
+	"Cmicro-Compiler/token"
+	"fmt"
+)
+
+/**
+ * @File: types
+ * @Description: 静态类型检查，在求值之前遍历抽象语法树，提前报告类型错误
+ */
+
+type Type string
+
+// 静态类型
+const (
+	INT      = "int"
+	STRING   = "string"
+	BOOL     = "bool"
+	ARRAY    = "array"
+	HASH     = "hash"
+	FUNCTION = "fn"
+	NULL     = "null"
+	ANY      = "any" // 无法在编译期确定的类型，不参与检查
+)
+
+// 可以在声明中使用的类型名
+var typeNames = map[string]Type{
+	"int":    INT,
+	"string": STRING,
+	"bool":   BOOL,
+}
+
+// LookupType 根据类型名返回对应的类型
+func LookupType(name string) (Type, bool) {
+	t, ok := typeNames[name]
+	return t, ok
+}
+
+// Diagnostic 类型检查诊断信息
+type Diagnostic struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s", d.Line, d.Column, d.Message)
+}
+
+// Signature 函数签名，未标注类型的参数和返回值为 ANY
+type Signature struct {
+	Params []Type
+	Return Type
+}
+
+// 符号
+type symbol struct {
+	typ      Type
+	declared bool       // 是否显式声明了类型，显式声明的变量不能被赋予其他类型的值
+	sig      *Signature // 变量绑定到函数字面量时记录其签名
+}
+
+// 作用域
+type scope struct {
+	symbols map[string]*symbol
+	outer   *scope
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{symbols: make(map[string]*symbol), outer: outer}
+}
+
+func (s *scope) lookup(name string) (*symbol, bool) {
+	sym, ok := s.symbols[name]
+	if !ok && s.outer != nil {
+		return s.outer.lookup(name)
+	}
+	return sym, ok
+}
+
+// 结构体声明，结构体名同时作为类型名使用
+type structInfo struct {
+	fields []string
+	types  []Type // 字段类型，未标注的为 ANY
+}
+
+// field 返回字段类型，字段不存在时返回 false
+func (s *structInfo) field(name string) (Type, bool) {
+	for i, field := range s.fields {
+		if field == name {
+			return s.types[i], true
+		}
+	}
+	return ANY, false
+}
+
+// Checker 类型检查器，作用域在多次 Check 之间保留，便于在 repl 中逐行检查
+type Checker struct {
+	scope       *scope
+	structs     map[string]*structInfo
+	returns     []Type // 当前所在函数声明的返回值类型
+	diagnostics []Diagnostic
+}
+
+func New() *Checker {
+	return &Checker{scope: newScope(nil), structs: make(map[string]*structInfo)}
+}
+
+// Check 对整个程序进行类型检查，返回本次检查发现的诊断信息
+func Check(program *ast.Program) []Diagnostic {
+	return New().Check(program)
+}
+
+// Check 检查程序，返回本次检查发现的诊断信息
+func (c *Checker) Check(program *ast.Program) []Diagnostic {
+	c.diagnostics = []Diagnostic{}
+	for _, stmt := range program.Statements {
+		c.checkStatement(stmt)
+	}
+	return c.diagnostics
+}
+
+// 记录一条诊断信息
+func (c *Checker) errorf(tok token.Token, format string, a ...interface{}) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// lookupType 查找内置类型名或已声明的结构体名
+func (c *Checker) lookupType(name string) (Type, bool) {
+	if t, ok := LookupType(name); ok {
+		return t, true
+	}
+	if _, ok := c.structs[name]; ok {
+		return Type(name), true
+	}
+	return ANY, false
+}
+
+// 解析类型标注，未知类型报错后按 ANY 处理
+func (c *Checker) resolveType(ident *ast.Identifier) Type {
+	if ident == nil {
+		return ANY
+	}
+	if t, ok := c.lookupType(ident.Value); ok {
+		return t
+	}
+	c.errorf(ident.Token, "unknown type: %s", ident.Value)
+	return ANY
+}
+
+// assignable 判断 src 类型的值能否赋给 dst 类型
+func assignable(src, dst Type) bool {
+	return src == dst || src == ANY || dst == ANY
+}
+
+// checkStatement 检查语句
+func (c *Checker) checkStatement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		c.checkLetStatement(stmt)
+	case *ast.AssignStatement:
+		c.checkAssignStatement(stmt)
+	case *ast.MemberAssignStatement:
+		c.checkMemberAssignStatement(stmt)
+	case *ast.StructStatement:
+		c.checkStructStatement(stmt)
+	case *ast.ReturnStatement:
+		t := c.infer(stmt.ReturnValue)
+		if len(c.returns) > 0 {
+			want := c.returns[len(c.returns)-1]
+			if !assignable(t, want) {
+				c.errorf(stmt.Token, "cannot return %s value from function returning %s", t, want)
+			}
+		}
+	case *ast.ExpressionStatement:
+		c.infer(stmt.Expression)
+	case *ast.ExportStatement:
+		c.checkLetStatement(stmt.Declaration)
+	case *ast.ThrowStatement:
+		c.infer(stmt.Value)
+	case *ast.TryStatement:
+		c.checkBlock(stmt.Block)
+		if stmt.CatchParam != nil {
+			c.scope.symbols[stmt.CatchParam.Value] = &symbol{typ: ANY}
+		}
+		c.checkBlock(stmt.Catch)
+		c.checkBlock(stmt.Finally)
+	case *ast.ImportStatement:
+		c.scope.symbols[stmt.Name()] = &symbol{typ: ANY}
+	}
+}
+
+// checkBlock 检查语句块，语句块与外层共享作用域，与求值器保持一致
+func (c *Checker) checkBlock(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	for _, stmt := range block.Statements {
+		c.checkStatement(stmt)
+	}
+}
+
+// checkLetStatement 检查变量声明
+func (c *Checker) checkLetStatement(stmt *ast.LetStatement) {
+	if stmt == nil || stmt.Name == nil {
+		return
+	}
+	t := c.infer(stmt.Value)
+	sym := &symbol{typ: t, sig: c.signatureOf(stmt.Value)}
+
+	if stmt.Type != nil {
+		declared := c.resolveType(stmt.Type)
+		if !assignable(t, declared) {
+			c.errorf(stmt.Token, "cannot use %s value as %s in declaration of %s", t, declared, stmt.Name.Value)
+		}
+		sym.typ = declared
+		sym.declared = true
+	}
+	c.scope.symbols[stmt.Name.Value] = sym
+}
+
+// checkAssignStatement 检查赋值语句
+func (c *Checker) checkAssignStatement(stmt *ast.AssignStatement) {
+	t := c.infer(stmt.Value)
+	sym, ok := c.scope.lookup(stmt.Name.Value)
+	if !ok {
+		return // 未声明的变量由求值器在运行时报告
+	}
+	if sym.declared {
+		if !assignable(t, sym.typ) {
+			c.errorf(stmt.Token, "cannot assign %s value to %s variable %s", t, sym.typ, stmt.Name.Value)
+		}
+		return
+	}
+	// let 声明的变量允许改变类型，此后不再对其做类型推断
+	if sym.typ != t {
+		sym.typ = ANY
+	}
+	sym.sig = c.signatureOf(stmt.Value)
+}
+
+// checkStructStatement 检查结构体声明，并登记结构体名为类型名
+func (c *Checker) checkStructStatement(stmt *ast.StructStatement) {
+	info := &structInfo{}
+	// 先登记结构体名，字段类型可以引用结构体自身
+	c.structs[stmt.Name.Value] = info
+	c.scope.symbols[stmt.Name.Value] = &symbol{typ: ANY}
+
+	for i, field := range stmt.Fields {
+		if _, ok := info.field(field.Value); ok {
+			c.errorf(field.Token, "duplicate field %s in struct %s", field.Value, stmt.Name.Value)
+			continue
+		}
+		info.fields = append(info.fields, field.Value)
+		info.types = append(info.types, c.resolveType(stmt.FieldTypes[i]))
+	}
+}
+
+// checkMemberAssignStatement 检查成员赋值语句
+func (c *Checker) checkMemberAssignStatement(stmt *ast.MemberAssignStatement) {
+	want := c.infer(stmt.Target)
+	t := c.infer(stmt.Value)
+	if !assignable(t, want) {
+		c.errorf(stmt.Token, "cannot assign %s value to field %s of type %s", t, stmt.Target.Property.Value, want)
+	}
+}
+
+// inferStructLiteral 检查结构体字面量的字段名与字段值类型
+func (c *Checker) inferStructLiteral(exp *ast.StructLiteral) Type {
+	info, ok := c.structs[exp.Name.Value]
+	for i, field := range exp.Fields {
+		t := c.infer(exp.Values[i])
+		if !ok {
+			continue
+		}
+		want, found := info.field(field.Value)
+		if !found {
+			c.errorf(field.Token, "struct %s has no field %s", exp.Name.Value, field.Value)
+		} else if !assignable(t, want) {
+			c.errorf(field.Token, "cannot use %s value as %s in field %s of %s", t, want, field.Value, exp.Name.Value)
+		}
+	}
+	if !ok {
+		return ANY // 未声明的结构体由求值器在运行时报告
+	}
+	return Type(exp.Name.Value)
+}
+
+// inferMemberExpression 推断成员访问的类型，已知结构体类型时检查字段是否存在
+func (c *Checker) inferMemberExpression(exp *ast.MemberExpression) Type {
+	t := c.infer(exp.Object)
+	info, ok := c.structs[string(t)]
+	if !ok {
+		return ANY
+	}
+	field, ok := info.field(exp.Property.Value)
+	if !ok {
+		c.errorf(exp.Property.Token, "struct %s has no field %s", t, exp.Property.Value)
+	}
+	return field
+}
+
+// infer 推断表达式类型，同时检查子表达式
+func (c *Checker) infer(exp ast.Expression) Type {
+	switch exp := exp.(type) {
+	case *ast.IntegerLiteral:
+		return INT
+	case *ast.StringLiteral:
+		return STRING
+	case *ast.Boolean:
+		return BOOL
+	case *ast.Identifier:
+		if sym, ok := c.scope.lookup(exp.Value); ok {
+			return sym.typ
+		}
+		return ANY
+	case *ast.PrefixExpression:
+		return c.inferPrefixExpression(exp)
+	case *ast.InfixExpression:
+		return c.inferInfixExpression(exp)
+	case *ast.IfExpression:
+		c.infer(exp.Condition)
+		c.checkBlock(exp.Consequence)
+		c.checkBlock(exp.Alternative)
+		return ANY
+	case *ast.ForExpression:
+		c.checkLetStatement(exp.Init)
+		c.infer(exp.Condition)
+		if exp.Post != nil {
+			c.infer(exp.Post.Expression)
+		}
+		c.checkBlock(exp.Body)
+		return ANY
+	case *ast.ForInExpression:
+		c.checkForInExpression(exp)
+		return ANY
+	case *ast.FunctionLiteral:
+		c.checkFunctionLiteral(exp)
+		return FUNCTION
+	case *ast.CallExpression:
+		return c.inferCallExpression(exp)
+	case *ast.ArrayLiteral:
+		for _, el := range exp.Elements {
+			c.infer(el)
+		}
+		return ARRAY
+	case *ast.IndexExpression:
+		return c.inferIndexExpression(exp)
+	case *ast.SliceExpression:
+		return c.inferSliceExpression(exp)
+	case *ast.MemberExpression:
+		return c.inferMemberExpression(exp)
+	case *ast.StructLiteral:
+		return c.inferStructLiteral(exp)
+	case *ast.HashLiteral:
+		for _, key := range exp.Keys {
+			kt := c.infer(key)
+			if kt == ARRAY || kt == HASH || kt == FUNCTION {
+				c.errorf(exp.Token, "unusable as hash key: %s", kt)
+			}
+			c.infer(exp.Pairs[key])
+		}
+		return HASH
+	}
+	return ANY
+}
+
+// inferPrefixExpression 推断前缀表达式类型
+func (c *Checker) inferPrefixExpression(exp *ast.PrefixExpression) Type {
+	right := c.infer(exp.Right)
+	if exp.Operator == "!" {
+		return BOOL
+	}
+	if right != INT && right != ANY {
+		c.errorf(exp.Token, "unknown operator: %s%s", exp.Operator, right)
+		return ANY
+	}
+	return INT
+}
+
+// inferInfixExpression 推断中缀表达式类型，与求值器中 evalInfixExpression 的规则保持一致
+func (c *Checker) inferInfixExpression(exp *ast.InfixExpression) Type {
+	left := c.infer(exp.Left)
+	right := c.infer(exp.Right)
+	op := exp.Operator
+
+	switch op {
+	case "==", "!=":
+		return BOOL
+	case "<", ">", "<=", ">=":
+		if left == ANY || right == ANY || left == right && (left == INT || left == STRING) {
+			return BOOL
+		}
+	case "in":
+		return c.inferInExpression(exp, left, right)
+	case "*":
+		if left == STRING && (right == INT || right == ANY) || right == STRING && (left == INT || left == ANY) {
+			return STRING
+		}
+		if left == ANY || right == ANY {
+			return ANY
+		}
+		if left == INT && right == INT {
+			return INT
+		}
+	case "+":
+		if left == ANY || right == ANY {
+			return ANY
+		}
+		if left == INT && right == INT || left == STRING && right == STRING {
+			return left
+		}
+	case "-", "/", "%":
+		if left == ANY && right == ANY {
+			return ANY
+		}
+		if (left == INT || left == ANY) && (right == INT || right == ANY) {
+			return INT
+		}
+	default:
+		return ANY
+	}
+
+	if left == ANY || right == ANY {
+		return ANY
+	}
+	if left != right {
+		c.errorf(exp.Token, "type mismatch: %s %s %s", left, op, right)
+	} else {
+		c.errorf(exp.Token, "unknown operator: %s %s %s", left, op, right)
+	}
+	return ANY
+}
+
+// inferInExpression 检查包含运算的操作数，结果总是 bool
+func (c *Checker) inferInExpression(exp *ast.InfixExpression, left, right Type) Type {
+	switch {
+	case right == STRING && left != STRING && left != ANY:
+		c.errorf(exp.Token, "type mismatch: %s in %s", left, right)
+	case right == HASH && (left == ARRAY || left == HASH || left == FUNCTION):
+		c.errorf(exp.Token, "unusable as hash key: %s", left)
+	case right != STRING && right != ARRAY && right != HASH && right != ANY:
+		c.errorf(exp.Token, "unknown operator: %s in %s", left, right)
+	}
+	return BOOL
+}
+
+// checkForInExpression 检查 for-in 循环，循环变量只在循环体内可见
+func (c *Checker) checkForInExpression(exp *ast.ForInExpression) {
+	key, value := Type(INT), Type(ANY)
+	switch t := c.infer(exp.Iterable); t {
+	case ARRAY:
+	case STRING:
+		value = STRING
+	case HASH, ANY:
+		key = ANY
+	default:
+		c.errorf(exp.Token, "cannot iterate over %s", t)
+	}
+
+	outer := c.scope
+	c.scope = newScope(outer)
+	defer func() { c.scope = outer }()
+
+	if exp.Key != nil {
+		c.scope.symbols[exp.Key.Value] = &symbol{typ: key}
+	}
+	c.scope.symbols[exp.Value.Value] = &symbol{typ: value}
+	c.checkBlock(exp.Body)
+}
+
+// inferIndexExpression 推断索引表达式类型
+func (c *Checker) inferIndexExpression(exp *ast.IndexExpression) Type {
+	left := c.infer(exp.Left)
+	index := c.infer(exp.Index)
+
+	switch left {
+	case ARRAY:
+		if index != INT && index != ANY {
+			c.errorf(exp.Token, "index operator not supported: %s[%s]", left, index)
+		}
+	case STRING:
+		if index != INT && index != ANY {
+			c.errorf(exp.Token, "index operator not supported: %s[%s]", left, index)
+		}
+		return STRING
+	case HASH, ANY:
+	default:
+		c.errorf(exp.Token, "index operator not supported: %s", left)
+	}
+	return ANY
+}
+
+// inferSliceExpression 推断切片表达式类型，切片结果与被切片的值类型相同
+func (c *Checker) inferSliceExpression(exp *ast.SliceExpression) Type {
+	left := c.infer(exp.Left)
+	for _, bound := range []ast.Expression{exp.Start, exp.End} {
+		if bound == nil {
+			continue
+		}
+		if t := c.infer(bound); t != INT && t != ANY {
+			c.errorf(exp.Token, "slice index must be int, got %s", t)
+		}
+	}
+
+	switch left {
+	case ARRAY, STRING, ANY:
+		return left
+	default:
+		c.errorf(exp.Token, "slice operator not supported: %s", left)
+		return ANY
+	}
+}
+
+// checkFunctionLiteral 在新作用域中检查函数体
+func (c *Checker) checkFunctionLiteral(fl *ast.FunctionLiteral) {
+	sig := c.signatureOf(fl)
+
+	outer := c.scope
+	c.scope = newScope(outer)
+	defer func() { c.scope = outer }()
+
+	for i, param := range fl.Parameters {
+		declared := i < len(fl.ParameterTypes) && fl.ParameterTypes[i] != nil
+		c.scope.symbols[param.Value] = &symbol{typ: sig.Params[i], declared: declared}
+	}
+
+	for _, t := range fl.ParameterTypes {
+		if t != nil {
+			c.resolveType(t)
+		}
+	}
+	if fl.ReturnType != nil {
+		c.resolveType(fl.ReturnType)
+	}
+
+	c.returns = append(c.returns, sig.Return)
+	defer func() { c.returns = c.returns[:len(c.returns)-1] }()
+
+	if fl.Body == nil {
+		return
+	}
+	for i, stmt := range fl.Body.Statements {
+		// 函数体最后一个表达式的值作为隐式返回值
+		last, ok := stmt.(*ast.ExpressionStatement)
+		if ok && fl.ReturnType != nil && i == len(fl.Body.Statements)-1 {
+			if t := c.infer(last.Expression); !assignable(t, sig.Return) {
+				c.errorf(last.Token, "cannot return %s value from function returning %s", t, sig.Return)
+			}
+			continue
+		}
+		c.checkStatement(stmt)
+	}
+}
+
+// signatureOf 获取表达式对应的函数签名，不是函数时返回 nil
+func (c *Checker) signatureOf(exp ast.Expression) *Signature {
+	switch exp := exp.(type) {
+	case *ast.FunctionLiteral:
+		sig := &Signature{Params: make([]Type, len(exp.Parameters)), Return: ANY}
+		for i := range exp.Parameters {
+			sig.Params[i] = ANY
+			if i < len(exp.ParameterTypes) && exp.ParameterTypes[i] != nil {
+				if t, ok := c.lookupType(exp.ParameterTypes[i].Value); ok {
+					sig.Params[i] = t
+				}
+			}
+		}
+		if exp.ReturnType != nil {
+			if t, ok := c.lookupType(exp.ReturnType.Value); ok {
+				sig.Return = t
+			}
+		}
+		return sig
+	case *ast.Identifier:
+		if sym, ok := c.scope.lookup(exp.Value); ok {
+			return sym.sig
+		}
+	}
+	return nil
+}
+
+// inferCallExpression 检查函数调用的参数个数与类型
+func (c *Checker) inferCallExpression(exp *ast.CallExpression) Type {
+	c.infer(exp.Function)
+	args := make([]Type, len(exp.Arguments))
+	for i, arg := range exp.Arguments {
+		args[i] = c.infer(arg)
+	}
+
+	sig := c.signatureOf(exp.Function)
+	if sig == nil {
+		return ANY
+	}
+	name := exp.Function.String()
+	if len(args) != len(sig.Params) {
+		c.errorf(exp.Token, "wrong number of arguments to %s: got=%d, want=%d", name, len(args), len(sig.Params))
+		return sig.Return
+	}
+	for i, arg := range args {
+		if !assignable(arg, sig.Params[i]) {
+			c.errorf(exp.Token, "cannot use %s value as %s in argument %d to %s", arg, sig.Params[i], i+1, name)
+		}
+	}
+	return sig.Return
+}
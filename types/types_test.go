@@ -0,0 +1,87 @@
+package types
+
+import (
+	"Cmicro-Compiler/lexer"
+	"Cmicro-Compiler/parser"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`let a = 1; a + 2;`, []string{}},
+		{`"a" - 1;`, []string{"1:5: type mismatch: string - int"}},
+		{`"a" * "b";`, []string{"1:5: unknown operator: string * string"}},
+		{`-true;`, []string{"1:1: unknown operator: -bool"}},
+		{`int x = 1; string s = "a"; bool f = true;`, []string{}},
+		{`int x = "a";`, []string{"1:1: cannot use string value as int in declaration of x"}},
+		{`int x = 1;
+x = "a";`, []string{"2:1: cannot assign string value to int variable x"}},
+		{`let x = 1; x = "a"; x - 1;`, []string{}},
+		{`float f = 1;`, []string{"1:1: unknown type: float"}},
+		{`let add = fn(int a, int b) int { a + b };
+add(1, "2");`, []string{`2:4: cannot use string value as int in argument 2 to add`}},
+		{`let add = fn(int a, int b) int { a + b }; add(1);`,
+			[]string{"1:46: wrong number of arguments to add: got=1, want=2"}},
+		{`let f = fn(int a) string { return a; };`,
+			[]string{"1:28: cannot return int value from function returning string"}},
+		{`let f = fn(string s) int { s };`,
+			[]string{"1:28: cannot return string value from function returning int"}},
+		{`let f = fn(a, b) { a - b }; f("x", 1);`, []string{}},
+		{`for(int i = 0; i < "5"; ++i){ i; }`, []string{"1:18: type mismatch: int < string"}},
+		{`{[1]: 2};`, []string{"1:1: unusable as hash key: array"}},
+		{`struct P { int x, y }; P p = P{x: 1, y: "a"}; p.x + 1;`, []string{}},
+		{`struct P { int x }; P{x: "a", z: 1};`, []string{
+			"1:23: cannot use string value as int in field x of P",
+			"1:31: struct P has no field z",
+		}},
+		{`struct P { int x }; let p = P{}; p.x = "a"; p.y;`, []string{
+			"1:38: cannot assign string value to field x of type int",
+			"1:47: struct P has no field y",
+		}},
+		{`"a" < "b"; "a" * 2; "abc"[0] + "d"; "a" in ["a"]; 1 <= 2;`, []string{}},
+		{`"a" < 1; 1 in "a"; "a" - "b"[0];`, []string{
+			"1:5: type mismatch: string < int",
+			"1:12: type mismatch: int in string",
+			"1:24: unknown operator: string - string",
+		}},
+		{`for (i, ch in "ab") { i + 1; ch + "c"; } for (x in 1) { x; }`, []string{"1:42: cannot iterate over int"}},
+		{`int n = 0; for (x in ["a"]) { string n = x; } n + 1;`, []string{}},
+		{`int n = int("4"); string s = str(n); bool b = bool(s); type(b);`, []string{}},
+		{`struct P { x, x };`, []string{"1:15: duplicate field x in struct P"}},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("parser errors for %q: %v", tt.input, p.Errors())
+		}
+
+		diagnostics := Check(program)
+		if len(diagnostics) != len(tt.expected) {
+			t.Errorf("wrong number of diagnostics for %q. got=%v, want=%v", tt.input, diagnostics, tt.expected)
+			continue
+		}
+		for i, d := range diagnostics {
+			if d.String() != tt.expected[i] {
+				t.Errorf("wrong diagnostic for %q. got=%q, want=%q", tt.input, d.String(), tt.expected[i])
+			}
+		}
+	}
+}
+
+func TestCheckerKeepsScope(t *testing.T) {
+	checker := New()
+	inputs := []string{`int x = 1;`, `x = "a";`}
+
+	var diagnostics []Diagnostic
+	for _, input := range inputs {
+		diagnostics = checker.Check(parser.New(lexer.New(input)).ParseProgram())
+	}
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got=%v", diagnostics)
+	}
+}